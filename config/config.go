@@ -5,26 +5,151 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
 
-func GetConfig[T any](reader io.Reader) (*T, error) {
+// source is a single raw config document, name is used to prefix errors
+type source struct {
+	name   string
+	format Format
+	data   []byte
+}
+
+// GetConfig applies `default` tags, resolves ${VAR} and ${file:/path} references, decodes
+// the document into T and then overlays any field whose `envconfig` variable is set,
+// followed by any flags passed with WithFlags.
+// The document is YAML unless another format is chosen with WithFormat
+func GetConfig[T any](reader io.Reader, options ...Option) (*T, error) {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	opts := newOptions(options...)
+	return load[T]([]source{{name: "config", format: opts.format, data: b}}, opts)
+}
+
+// LoadYAMLDocument reads the document at path. When a profile is set with
+// WithProfile the matching overlays are merged over it, see ProfilePaths
+func LoadYAMLDocument[T any](path string, options ...Option) (*T, error) {
+	opts := newOptions(options...)
+	if opts.profile == "" {
+		return LoadLayered[T]([]string{path}, options...)
+	}
+
+	paths, err := ProfilePaths(path, opts.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadLayered[T](paths, options...)
+}
+
+// LoadLayered deep merges each document over the previous one before decoding into T.
+// Mappings are merged key by key, lists and scalars are replaced.
+// Each file's format is picked by its extension unless WithFormat is set
+func LoadLayered[T any](paths []string, options ...Option) (*T, error) {
+	opts := newOptions(options...)
+
+	sources := make([]source, 0, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+
+		format := opts.format
+		if format == "" {
+			format = FormatFromPath(path)
+		}
+		sources = append(sources, source{name: path, format: format, data: b})
+	}
+
+	return load[T](sources, opts)
+}
+
+func load[T any](sources []source, opts loadOptions) (*T, error) {
+	prov := opts.provenance
+	prov.reset()
+
 	var cfg T
-	decoder := yaml.NewDecoder(reader)
-	decoder.KnownFields(true)
-	if err := decoder.Decode(&cfg); err != nil {
-		return nil, fmt.Errorf("the config content is malformed: %w", err)
+	if err := applyDefaults(&cfg); err != nil {
+		return nil, fmt.Errorf("apply defaults: %w", err)
+	}
+	prov.recordDefaults(reflect.TypeFor[T]())
+
+	var merged *yaml.Node
+	for i, src := range sources {
+		node, err := parseSource[T](src, opts)
+		if err != nil {
+			return nil, fmt.Errorf("the config content is malformed: %s: %w", src.name, err)
+		}
+		merged = mergeNodes(merged, node)
+
+		kind := SourceFile
+		if i > 0 {
+			kind = SourceOverlay
+		}
+		prov.recordNode(node, "", Source{Kind: kind, Name: src.name})
+	}
+
+	// empty and comment only documents leave cfg to the defaults, env and flags
+	if merged != nil && merged.Kind != 0 {
+		b, err := yaml.Marshal(merged)
+		if err != nil {
+			return nil, fmt.Errorf("encode merged config: %w", err)
+		}
+		if err := decodeStrict(bytes.NewReader(b), &cfg); err != nil {
+			return nil, fmt.Errorf("the config content is malformed: %w", err)
+		}
+	}
+
+	if err := applyEnv(&cfg, opts.lookupEnv); err != nil {
+		return nil, fmt.Errorf("apply env: %w", err)
+	}
+	prov.recordEnv(reflect.TypeFor[T](), opts.lookupEnv)
+
+	if err := applyFlags(&cfg, opts.flags, prov); err != nil {
+		return nil, fmt.Errorf("apply flags: %w", err)
+	}
+
+	if opts.validate {
+		if err := Validate(&cfg); err != nil {
+			return nil, err
+		}
 	}
 
 	return &cfg, nil
 }
 
-func LoadYAMLDocument[T any](path string) (*T, error) {
-	b, err := os.ReadFile(path)
+// parseSource parses a single document in any Format and resolves its references. It is checked against T on its
+// own so errors point at the file and line they came from rather than the merged result
+func parseSource[T any](src source, opts loadOptions) (*yaml.Node, error) {
+	node, err := parseDocument(src.data, src.format, reflect.TypeFor[T]())
 	if err != nil {
-		return nil, fmt.Errorf("read file: %w", err)
+		return nil, err
+	}
+	if node.Kind == 0 {
+		return node, nil
+	}
+
+	if err := interpolate(node, opts.lookupEnv); err != nil {
+		return nil, err
+	}
+	if err := checkKnownFields(node, reflect.TypeFor[T]()); err != nil {
+		return nil, err
+	}
+	if err := node.Decode(new(T)); err != nil {
+		return nil, err
 	}
 
-	return GetConfig[T](bytes.NewReader(b))
+	return node, nil
+}
+
+func decodeStrict(r io.Reader, out any) error {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	return decoder.Decode(out)
 }
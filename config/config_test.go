@@ -0,0 +1,124 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+type defaultsConfig struct {
+	Name string `yaml:"name" default:"app"`
+	Port int    `yaml:"port" default:"8080" envconfig:"TEST_PORT"`
+}
+
+func lookupMap(env map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestGetConfigNonStruct(t *testing.T) {
+	cfg, err := GetConfig[map[string]any](strings.NewReader("a: 1\nb: two\n"), WithLookupEnv(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*cfg)["a"] != 1 || (*cfg)["b"] != "two" {
+		t.Errorf("GetConfig() = %v, want a=1 b=two", *cfg)
+	}
+
+	if _, err := GetConfig[map[string]any](strings.NewReader("a: 1\n"), WithValidation(), WithLookupEnv(nil)); err != nil {
+		t.Errorf("GetConfig() with validation error = %v, want nil", err)
+	}
+}
+
+func TestGetConfigEmptyDocument(t *testing.T) {
+	for name, doc := range map[string]string{
+		"empty":        "",
+		"comment only": "# nothing set here\n",
+	} {
+		cfg, err := GetConfig[defaultsConfig](strings.NewReader(doc), WithLookupEnv(lookupMap(map[string]string{"TEST_PORT": "9090"})))
+		if err != nil {
+			t.Errorf("%s: GetConfig() error = %v", name, err)
+			continue
+		}
+		if cfg.Name != "app" || cfg.Port != 9090 {
+			t.Errorf("%s: GetConfig() = %+v, want the default name and env port", name, *cfg)
+		}
+	}
+}
+
+type mergeConfig struct {
+	A struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+	} `yaml:"a"`
+	B struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+	} `yaml:"b"`
+}
+
+func TestGetConfigMergeKeys(t *testing.T) {
+	doc := "a: &base\n  host: h\n  port: 1\nb:\n  <<: *base\n  port: 2\n"
+	cfg, err := GetConfig[mergeConfig](strings.NewReader(doc), WithLookupEnv(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.B.Host != "h" || cfg.B.Port != 2 {
+		t.Errorf("GetConfig() b = %+v, want host h and port 2", cfg.B)
+	}
+
+	doc = "a: &base\n  host: h\nb:\n  <<: [*base, {bogus: 1}]\n"
+	_, err = GetConfig[mergeConfig](strings.NewReader(doc), WithLookupEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), "field bogus not found") {
+		t.Errorf("GetConfig() error = %v, want field bogus not found", err)
+	}
+
+	doc = "a: &base\n  host: h\n  bogus: 1\nb: *base\n"
+	_, err = GetConfig[mergeConfig](strings.NewReader(doc), WithLookupEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), "field bogus not found") {
+		t.Errorf("GetConfig() error = %v, want field bogus not found", err)
+	}
+}
+
+func TestGetConfigTOMLErrors(t *testing.T) {
+	_, err := GetConfig[defaultsConfig](strings.NewReader("name = \"a\"\nbogus = 1\n"), WithFormat(FormatTOML), WithLookupEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), "field bogus not found") || strings.Contains(err.Error(), "line 0") {
+		t.Errorf("GetConfig() error = %v, want field bogus not found without a line", err)
+	}
+
+	_, err = GetConfig[defaultsConfig](strings.NewReader("name: a\nbogus: 1\n"), WithLookupEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), "line 2: field bogus not found") {
+		t.Errorf("GetConfig() error = %v, want the YAML line", err)
+	}
+}
+
+type optionalSectionConfig struct {
+	Name string `yaml:"name"`
+	Opt  *struct {
+		Host string `yaml:"host" envconfig:"RV_HOST"`
+	} `yaml:"opt"`
+}
+
+func TestEnvAllocatesOptionalSection(t *testing.T) {
+	prov := NewProvenance()
+	cfg, err := GetConfig[optionalSectionConfig](strings.NewReader("name: a\n"),
+		WithLookupEnv(lookupMap(map[string]string{"RV_HOST": "h"})), WithProvenance(prov))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Opt == nil || cfg.Opt.Host != "h" {
+		t.Fatalf("GetConfig() opt = %+v, want host h from RV_HOST", cfg.Opt)
+	}
+	if s, _ := prov.Source("opt.host"); s.String() != "env RV_HOST" {
+		t.Errorf("Source(opt.host) = %v, want env RV_HOST", s)
+	}
+
+	cfg, err = GetConfig[optionalSectionConfig](strings.NewReader("name: a\n"), WithLookupEnv(lookupMap(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Opt != nil {
+		t.Errorf("GetConfig() opt = %+v, want nil without RV_HOST", cfg.Opt)
+	}
+}
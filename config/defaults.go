@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// applyDefaults sets every zero field tagged with `default:"..."`.
+// It runs before any source is decoded so YAML, env and flags can override it
+func applyDefaults(cfg any) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("expected a non nil pointer, got %T", cfg)
+	}
+
+	return walkDefaults(v.Elem())
+}
+
+func walkDefaults(v reflect.Value) error {
+	// only structs carry default tags, any other config type is left as is
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		def, ok := field.Tag.Lookup("default")
+		if !ok {
+			if isLeaf(field.Type) {
+				continue
+			}
+			if fv.Kind() == reflect.Pointer {
+				// only allocate optional sections when there is something to default
+				if fv.IsNil() && !hasDefaults(field.Type.Elem()) {
+					continue
+				}
+				if fv.IsNil() {
+					fv.Set(reflect.New(field.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := walkDefaults(fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !fv.IsZero() {
+			continue
+		}
+		if err := setFromString(fv, def); err != nil {
+			return fmt.Errorf("default %q on %s.%s cannot be parsed as %s: %w", def, t.Name(), field.Name, field.Type, err)
+		}
+	}
+
+	return nil
+}
+
+func hasDefaults(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("default"); ok {
+			return true
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if !isLeaf(ft) && hasDefaults(ft) {
+			return true
+		}
+	}
+
+	return false
+}
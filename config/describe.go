@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const secretMask = "*******"
+
+// Field is a single effective config value and where it came from
+type Field struct {
+	Path   string
+	Value  any
+	Source Source
+	Secret bool
+}
+
+// Describe lists every leaf of cfg in declaration order. Fields tagged `secret:"true"`
+// are masked. prov may be nil, in which case every source is reported as unset
+func Describe(cfg any, prov *Provenance) []Field {
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	leaves := leafFields(v.Type())
+	fields := make([]Field, 0, len(leaves))
+	for _, lf := range leaves {
+		source, _ := prov.Source(lf.path)
+		field := Field{
+			Path:   lf.path,
+			Source: source,
+			Secret: isSecret(lf.field),
+		}
+
+		fv, ok := lookupIndex(v, lf.index)
+		switch {
+		case !ok:
+			field.Value = nil
+		case field.Secret && !fv.IsZero():
+			field.Value = secretMask
+		default:
+			field.Value = fv.Interface()
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Dump writes the effective config as YAML, each value commented with its source
+func Dump(w io.Writer, cfg any, prov *Provenance) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range Describe(cfg, prov) {
+		value := &yaml.Node{}
+		if err := value.Encode(field.Value); err != nil {
+			return fmt.Errorf("encode %s: %w", field.Path, err)
+		}
+		value.LineComment = field.Source.String()
+		setNode(root, strings.Split(field.Path, "."), value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	return encoder.Close()
+}
+
+// DescribeAttr renders the effective config as a slog group nested by yaml path,
+// each leaf is a group holding its value and source
+func DescribeAttr(key string, cfg any, prov *Provenance) slog.Attr {
+	type group struct {
+		name     string
+		attrs    []any
+		children []*group
+	}
+
+	root := &group{name: key}
+	for _, field := range Describe(cfg, prov) {
+		parts := strings.Split(field.Path, ".")
+		g := root
+		for _, part := range parts[:len(parts)-1] {
+			var next *group
+			for _, child := range g.children {
+				if child.name == part {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				next = &group{name: part}
+				g.children = append(g.children, next)
+			}
+			g = next
+		}
+		g.attrs = append(g.attrs, slog.Group(parts[len(parts)-1],
+			slog.Any("value", field.Value),
+			slog.String("source", field.Source.String()),
+		))
+	}
+
+	var build func(g *group) slog.Attr
+	build = func(g *group) slog.Attr {
+		attrs := g.attrs
+		for _, child := range g.children {
+			attrs = append(attrs, build(child))
+		}
+		return slog.Group(g.name, attrs...)
+	}
+
+	return build(root)
+}
+
+// lookupIndex walks index without allocating, ok is false when a nil pointer is in the way
+func lookupIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+
+	return v, true
+}
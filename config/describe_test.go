@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type describeConfig struct {
+	Name  string `yaml:"name" default:"app"`
+	Host  string `yaml:"host"`
+	Port  int    `yaml:"port"`
+	Token string `yaml:"token" envconfig:"DESCRIBE_TOKEN" secret:"true"`
+	Level string `yaml:"level"`
+}
+
+func TestDescribeSources(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	overlay := filepath.Join(dir, "config.production.yaml")
+	writeFile(t, base, "host: base\nport: 1\n")
+	writeFile(t, overlay, "port: 2\n")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags[describeConfig](fs)
+	if err := fs.Parse([]string{"-level=debug"}); err != nil {
+		t.Fatal(err)
+	}
+
+	prov := NewProvenance()
+	cfg, err := LoadYAMLDocument[describeConfig](base,
+		WithProfile("production"),
+		WithLookupEnv(lookupMap(map[string]string{"DESCRIBE_TOKEN": "s3cret"})),
+		WithFlags(fs),
+		WithProvenance(prov),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Field{
+		{Path: "name", Value: "app", Source: Source{Kind: SourceDefault}},
+		{Path: "host", Value: "base", Source: Source{Kind: SourceFile, Name: base}},
+		{Path: "port", Value: 2, Source: Source{Kind: SourceOverlay, Name: overlay}},
+		{Path: "token", Value: secretMask, Source: Source{Kind: SourceEnv, Name: "DESCRIBE_TOKEN"}, Secret: true},
+		{Path: "level", Value: "debug", Source: Source{Kind: SourceFlag, Name: "-level"}},
+	}
+	got := Describe(cfg, prov)
+	if len(got) != len(want) {
+		t.Fatalf("Describe() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Describe()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var dump bytes.Buffer
+	if err := Dump(&dump, cfg, prov); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"name: app # default",
+		"port: 2 # overlay " + overlay,
+		"token: '" + secretMask + "' # env DESCRIBE_TOKEN",
+		"level: debug # flag -level",
+	} {
+		if !strings.Contains(dump.String(), line) {
+			t.Errorf("Dump() has no %q:\n%s", line, dump.String())
+		}
+	}
+
+	var attr bytes.Buffer
+	slog.New(slog.NewTextHandler(&attr, nil)).LogAttrs(context.Background(), slog.LevelInfo, "config", DescribeAttr("config", cfg, prov))
+	for _, want := range []string{"config.port.value=2", "config.token.value=" + secretMask, "config.token.source=\"env DESCRIBE_TOKEN\""} {
+		if !strings.Contains(attr.String(), want) {
+			t.Errorf("DescribeAttr() has no %s in %s", want, attr.String())
+		}
+	}
+
+	for name, out := range map[string]string{"Dump": dump.String(), "DescribeAttr": attr.String()} {
+		if strings.Contains(out, "s3cret") {
+			t.Errorf("%s leaked the secret: %s", name, out)
+		}
+	}
+}
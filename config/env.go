@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// LookupEnvFunc matches the signature of os.LookupEnv
+type LookupEnvFunc func(key string) (string, bool)
+
+// applyEnv overrides any field tagged with `envconfig:"NAME"` when NAME is set.
+// Nested structs are walked so DBConfig.CC and DBConfig.SS are both covered
+func applyEnv(cfg any, lookup LookupEnvFunc) error {
+	if lookup == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("expected a non nil pointer, got %T", cfg)
+	}
+
+	_, err := walkEnv(v.Elem(), lookup)
+	return err
+}
+
+// walkEnv reports whether any variable was applied. A nil pointer section is allocated
+// only when one of its variables is set, as flags do, so optional sections stay nil otherwise
+func walkEnv(v reflect.Value, lookup LookupEnvFunc) (bool, error) {
+	if v.Kind() == reflect.Pointer {
+		if !v.IsNil() {
+			return walkEnv(v.Elem(), lookup)
+		}
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return false, nil
+		}
+		section := reflect.New(v.Type().Elem())
+		applied, err := walkEnv(section.Elem(), lookup)
+		if applied && err == nil {
+			v.Set(section)
+		}
+		return applied, err
+	}
+	if v.Kind() != reflect.Struct {
+		return false, nil
+	}
+
+	applied := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		name := field.Tag.Get("envconfig")
+		if name == "" || name == "-" {
+			if !isLeaf(field.Type) {
+				nested, err := walkEnv(fv, lookup)
+				if err != nil {
+					return applied, err
+				}
+				applied = applied || nested
+			}
+			continue
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setFromString(fv, value); err != nil {
+			return applied, fmt.Errorf("env %s: cannot parse %q into %s.%s: %w", name, value, t.Name(), field.Name, err)
+		}
+		applied = true
+	}
+
+	return applied, nil
+}
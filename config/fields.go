@@ -0,0 +1,98 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// leafField is a single value in a config struct, addressed by its yaml path e.g. conn.port
+type leafField struct {
+	path  string
+	index []int
+	field reflect.StructField
+}
+
+// leafFields lists every leaf of t in declaration order. Nested structs are walked and
+// inline structs are flattened the same way yaml.v3 treats them
+func leafFields(t reflect.Type) []leafField {
+	return appendLeafFields(nil, t, "", nil)
+}
+
+func appendLeafFields(fields []leafField, t reflect.Type, path string, index []int) []leafField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+		_, flags, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if strings.Contains(flags, "inline") && !isLeaf(field.Type) {
+			fields = appendLeafFields(fields, field.Type, path, fieldIndex)
+			continue
+		}
+
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+
+		if isLeaf(field.Type) {
+			fields = append(fields, leafField{
+				path:  joinPath(path, name),
+				index: fieldIndex,
+				field: field,
+			})
+			continue
+		}
+		fields = appendLeafFields(fields, field.Type, joinPath(path, name), fieldIndex)
+	}
+
+	return fields
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex but allocates nil pointers along the way
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+
+	return v
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// yamlName returns the key yaml.v3 uses for a field, or "" when it is skipped
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// isSecret reports whether the field is tagged `secret:"true"`, its value and default are never shown
+func isSecret(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true"
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldFlag is the flag.Value bound to a single config leaf.
+// The raw string is kept so it can be applied after every other source
+type fieldFlag struct {
+	typ   reflect.Type
+	value string
+}
+
+func (f *fieldFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *fieldFlag) Set(s string) error {
+	// parse into a throwaway value so bad input fails during flag parsing
+	if err := setFromString(reflect.New(f.typ).Elem(), s); err != nil {
+		return err
+	}
+	f.value = s
+	return nil
+}
+
+func (f *fieldFlag) IsBoolFlag() bool {
+	return f.typ.Kind() == reflect.Bool
+}
+
+// BindFlags defines a flag on fs for every leaf of T, named by its yaml path e.g. -conn.host.
+// Pass the same FlagSet to WithFlags once it has been parsed, set flags take precedence over every other source
+func BindFlags[T any](fs *flag.FlagSet) {
+	for _, lf := range leafFields(reflect.TypeFor[T]()) {
+		if !settable(lf.field.Type) {
+			continue
+		}
+
+		fs.Var(&fieldFlag{typ: lf.field.Type}, lf.path, flagUsage(lf.field))
+	}
+}
+
+func flagUsage(field reflect.StructField) string {
+	var details []string
+	if name := field.Tag.Get("envconfig"); name != "" && name != "-" {
+		details = append(details, "env "+name)
+	}
+	if def, ok := field.Tag.Lookup("default"); ok && !isSecret(field) {
+		details = append(details, "default "+def)
+	}
+
+	// backquotes make flag.PrintDefaults show the type as the value name
+	usage := "`" + field.Type.String() + "`"
+	if len(details) > 0 {
+		usage += " (" + strings.Join(details, ", ") + ")"
+	}
+	return usage
+}
+
+// applyFlags sets every leaf whose flag was passed on the command line
+func applyFlags(cfg any, fs *flag.FlagSet, prov *Provenance) error {
+	if fs == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("expected a non nil pointer, got %T", cfg)
+	}
+
+	leaves := make(map[string]leafField)
+	for _, lf := range leafFields(v.Type()) {
+		leaves[lf.path] = lf
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		ff, ok := f.Value.(*fieldFlag)
+		if !ok || err != nil {
+			return
+		}
+		lf, ok := leaves[f.Name]
+		if !ok {
+			return
+		}
+
+		if setErr := setFromString(fieldByIndex(v.Elem(), lf.index), ff.value); setErr != nil {
+			err = fmt.Errorf("flag -%s: cannot parse %q: %w", f.Name, ff.value, setErr)
+			return
+		}
+		prov.set(lf.path, Source{Kind: SourceFlag, Name: "-" + f.Name})
+	})
+
+	return err
+}
+
+// settable reports whether setFromString can handle t
+func settable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		return settable(t.Elem())
+	case reflect.Map, reflect.Interface, reflect.Struct, reflect.Array, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+type flagConfig struct {
+	Name string `yaml:"name" default:"app"`
+	Conn struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port" envconfig:"FLAG_PORT"`
+	} `yaml:"conn"`
+	Tags []string `yaml:"tags"`
+}
+
+func TestFlagsOverrideOtherSources(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags[flagConfig](fs)
+	if err := fs.Parse([]string{"-name=cli", "-conn.host=cli-host", "-conn.port=3", "-tags=a,b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := "conn:\n  host: file-host\n  port: 1\ntags: [x]\n"
+	cfg, err := GetConfig[flagConfig](strings.NewReader(doc),
+		WithLookupEnv(lookupMap(map[string]string{"FLAG_PORT": "2"})), WithFlags(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "cli" || cfg.Conn.Host != "cli-host" || cfg.Conn.Port != 3 {
+		t.Errorf("GetConfig() = %+v, want name, host and port from the flags", *cfg)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "a" || cfg.Tags[1] != "b" {
+		t.Errorf("Tags = %v, want [a b]", cfg.Tags)
+	}
+}
+
+func TestFlagsOnlyApplyWhenSet(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags[flagConfig](fs)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := GetConfig[flagConfig](strings.NewReader("conn:\n  host: file-host\n"),
+		WithLookupEnv(lookupMap(map[string]string{"FLAG_PORT": "2"})), WithFlags(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "app" || cfg.Conn.Host != "file-host" || cfg.Conn.Port != 2 {
+		t.Errorf("GetConfig() = %+v, want the default, file and env values", *cfg)
+	}
+}
+
+func TestFlagsParseError(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	BindFlags[flagConfig](fs)
+
+	err := fs.Parse([]string{"-conn.port=abc"})
+	if err == nil || !strings.Contains(err.Error(), `invalid value "abc" for flag -conn.port`) {
+		t.Errorf("Parse() error = %v, want an invalid value for -conn.port", err)
+	}
+}
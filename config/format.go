@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatYAML   Format = "yaml"
+	FormatJSON   Format = "json"
+	FormatTOML   Format = "toml"
+	FormatDotenv Format = "dotenv"
+)
+
+// FormatFromPath picks the format by file extension, anything unrecognised is treated as YAML
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	case ".env":
+		return FormatDotenv
+	}
+
+	return FormatYAML
+}
+
+// parseDocument turns any supported format into a yaml document node so every
+// format shares the same interpolation, merging and strict decoding
+func parseDocument(data []byte, format Format, t reflect.Type) (*yaml.Node, error) {
+	var node yaml.Node
+	switch format {
+	case FormatYAML, "":
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+
+	case FormatJSON:
+		// JSON is valid YAML, parsing it as such keeps line numbers for later errors
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil && len(bytes.TrimSpace(data)) > 0 {
+			return nil, fmt.Errorf("json: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+
+	case FormatTOML:
+		var v map[string]any
+		if _, err := toml.Decode(string(data), &v); err != nil {
+			return nil, err
+		}
+		if len(v) == 0 {
+			return &node, nil
+		}
+		if err := node.Encode(v); err != nil {
+			return nil, err
+		}
+		return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&node}}, nil
+
+	case FormatDotenv:
+		return parseDotenv(data, t)
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// parseDotenv maps KEY=value lines onto the fields tagged `envconfig:"KEY"`.
+// Keys that do not match a field are rejected, the same as unknown YAML fields
+func parseDotenv(data []byte, t reflect.Type) (*yaml.Node, error) {
+	paths := make(map[string]string)
+	for _, lf := range leafFields(t) {
+		if name := lf.field.Tag.Get("envconfig"); name != "" && name != "-" {
+			paths[name] = lf.path
+		}
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, raw, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value", line)
+		}
+		key = strings.TrimSpace(key)
+
+		value, err := dotenvValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", line, key, err)
+		}
+
+		path, ok := paths[key]
+		if !ok {
+			return nil, fmt.Errorf("line %d: variable %s not found in type %s", line, key, t)
+		}
+		setNode(root, strings.Split(path, "."), &yaml.Node{Kind: yaml.ScalarNode, Value: value, Line: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(root.Content) == 0 {
+		return &yaml.Node{}, nil
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}, nil
+}
+
+func dotenvValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := strings.LastIndex(raw, `"`)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		return strconv.Unquote(raw[:end+1])
+
+	case strings.HasPrefix(raw, "'"):
+		end := strings.LastIndex(raw, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		return raw[1:end], nil
+	}
+
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw), nil
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const filePrefix = "file:"
+
+// interpolate resolves references inside every scalar value of node:
+//
+//	${VAR}             value of the env var VAR
+//	${VAR:-fallback}   fallback when VAR is unset or empty
+//	${file:/path}      contents of the file, without the trailing newline
+//	$${VAR}            the literal text ${VAR}
+//
+// Keys are left untouched
+func interpolate(node *yaml.Node, lookup LookupEnvFunc) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+
+		value, err := expand(node.Value, lookup)
+		if err != nil {
+			return atLine(node, err)
+		}
+		node.Value = value
+		// let plain scalars resolve again, so `port: ${DB_PORT}` still decodes into an int
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := interpolate(node.Content[i], lookup); err != nil {
+				return err
+			}
+		}
+
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := interpolate(child, lookup); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func expand(s string, lookup LookupEnvFunc) (string, error) {
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+
+		// $${ is an escaped reference
+		if start > 0 && s[start-1] == '$' {
+			sb.WriteString(s[:start-1])
+			sb.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference %q", s[start:])
+		}
+		end += start
+
+		value, err := resolveReference(s[start+2:end], lookup)
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(s[:start])
+		sb.WriteString(value)
+		s = s[end+1:]
+	}
+}
+
+func resolveReference(ref string, lookup LookupEnvFunc) (string, error) {
+	name, fallback, hasFallback := strings.Cut(ref, ":-")
+	if name == "" {
+		return "", fmt.Errorf("empty reference ${%s}", ref)
+	}
+
+	if path, ok := strings.CutPrefix(name, filePrefix); ok {
+		b, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			return strings.TrimRight(string(b), "\r\n"), nil
+		case hasFallback:
+			return fallback, nil
+		}
+		return "", fmt.Errorf("unresolved file reference %q: %w", path, err)
+	}
+
+	var (
+		value string
+		ok    bool
+	)
+	if lookup != nil {
+		value, ok = lookup(name)
+	}
+	switch {
+	case hasFallback && value == "":
+		return fallback, nil
+	case ok:
+		return value, nil
+	}
+
+	return "", fmt.Errorf("unresolved variable %q", name)
+}
+
+// fileReferences lists the paths of the ${file:/path} references in s, escaped references included
+func fileReferences(s string) []string {
+	var paths []string
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			return paths
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return paths
+		}
+		end += start
+
+		name, _, _ := strings.Cut(s[start+2:end], ":-")
+		if path, ok := strings.CutPrefix(name, filePrefix); ok {
+			paths = append(paths, path)
+		}
+		s = s[end+1:]
+	}
+}
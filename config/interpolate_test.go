@@ -0,0 +1,55 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type interpolateConfig struct {
+	Name string `yaml:"name"`
+	Port int    `yaml:"port"`
+}
+
+func TestInterpolate(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "name")
+	writeFile(t, secret, "from-file\n")
+
+	env := lookupMap(map[string]string{"NAME": "from-env", "EMPTY": "", "PORT": "8080"})
+	for name, tc := range map[string]struct {
+		doc     string
+		want    interpolateConfig
+		wantErr string
+	}{
+		"variable":           {doc: "name: ${NAME}\nport: ${PORT}\n", want: interpolateConfig{Name: "from-env", Port: 8080}},
+		"inside text":        {doc: "name: pre-${NAME}-post\n", want: interpolateConfig{Name: "pre-from-env-post"}},
+		"fallback unset":     {doc: "name: ${MISSING:-fallback}\n", want: interpolateConfig{Name: "fallback"}},
+		"fallback empty":     {doc: "name: ${EMPTY:-fallback}\n", want: interpolateConfig{Name: "fallback"}},
+		"fallback not used":  {doc: "name: ${NAME:-fallback}\n", want: interpolateConfig{Name: "from-env"}},
+		"empty without":      {doc: "name: x${EMPTY}\n", want: interpolateConfig{Name: "x"}},
+		"escaped":            {doc: "name: $${NAME}\n", want: interpolateConfig{Name: "${NAME}"}},
+		"file":               {doc: "name: ${file:" + secret + "}\n", want: interpolateConfig{Name: "from-file"}},
+		"file fallback":      {doc: "name: ${file:/does/not/exist:-fallback}\n", want: interpolateConfig{Name: "fallback"}},
+		"quoted stays text":  {doc: "name: \"${PORT}\"\n", want: interpolateConfig{Name: "8080"}},
+		"unresolved":         {doc: "name: a\nport: ${MISSING}\n", wantErr: `line 2: unresolved variable "MISSING"`},
+		"unterminated":       {doc: "name: ${NAME\n", wantErr: `line 1: unterminated reference "${NAME"`},
+		"missing file":       {doc: "name: ${file:/does/not/exist}\n", wantErr: `line 1: unresolved file reference "/does/not/exist"`},
+		"empty reference":    {doc: "name: ${}\n", wantErr: "line 1: empty reference ${}"},
+		"keys are untouched": {doc: "${NAME}: a\n", wantErr: "field ${NAME} not found"},
+	} {
+		cfg, err := GetConfig[interpolateConfig](strings.NewReader(tc.doc), WithLookupEnv(env))
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%s: GetConfig() error = %v, want %s", name, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: GetConfig() error = %v", name, err)
+			continue
+		}
+		if *cfg != tc.want {
+			t.Errorf("%s: GetConfig() = %+v, want %+v", name, *cfg, tc.want)
+		}
+	}
+}
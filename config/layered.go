@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// localProfile is always merged last when present so developers can keep untracked overrides
+const localProfile = "local"
+
+// ProfilePaths returns the files loaded for a profile, in merge order.
+// For config.yaml and "production" this is config.yaml, config.production.yaml
+// and config.local.yaml when it exists. The profile file itself must exist
+func ProfilePaths(path, profile string) ([]string, error) {
+	paths := []string{path}
+	if profile == "" {
+		return paths, nil
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+
+	profilePath := base + "." + profile + ext
+	if _, err := os.Stat(profilePath); err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile, err)
+	}
+	paths = append(paths, profilePath)
+
+	if profile == localProfile {
+		return paths, nil
+	}
+
+	localPath := base + "." + localProfile + ext
+	_, err := os.Stat(localPath)
+	switch {
+	case err == nil:
+		paths = append(paths, localPath)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("profile %q: %w", localProfile, err)
+	}
+
+	return paths, nil
+}
+
+// mergeNodes merges src over dst and returns the result.
+// Mappings are merged recursively, any other kind replaces what was there
+func mergeNodes(dst, src *yaml.Node) *yaml.Node {
+	if src == nil || src.Kind == 0 {
+		return dst
+	}
+	if dst == nil || dst.Kind == 0 {
+		return src
+	}
+
+	if dst.Kind == yaml.DocumentNode && src.Kind == yaml.DocumentNode {
+		if len(dst.Content) == 0 {
+			return src
+		}
+		if len(src.Content) > 0 {
+			dst.Content[0] = mergeNodes(dst.Content[0], src.Content[0])
+		}
+		return dst
+	}
+
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return src
+	}
+
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+
+		found := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == key.Value {
+				dst.Content[j+1] = mergeNodes(dst.Content[j+1], value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+
+	return dst
+}
+
+// setNode places value at path in a mapping, creating mappings as needed
+func setNode(node *yaml.Node, path []string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == path[0] {
+			setNode(node.Content[i+1], path[1:], value)
+			return
+		}
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}
+	if len(path) == 1 {
+		node.Content = append(node.Content, key, value)
+		return
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, key, child)
+	setNode(child, path[1:], value)
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type layeredConfig struct {
+	Name string `yaml:"name"`
+	DB   struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+		Pool struct {
+			Min int `yaml:"min"`
+			Max int `yaml:"max"`
+		} `yaml:"pool"`
+	} `yaml:"db"`
+	Hosts []string `yaml:"hosts"`
+}
+
+func TestProfilePaths(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	production := filepath.Join(dir, "config.production.yaml")
+	local := filepath.Join(dir, "config.local.yaml")
+	writeFile(t, base, "")
+	writeFile(t, production, "")
+
+	paths, err := ProfilePaths(base, "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(paths, []string{base, production}) {
+		t.Errorf("ProfilePaths() = %v, want base and production", paths)
+	}
+
+	writeFile(t, local, "")
+	paths, err = ProfilePaths(base, "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(paths, []string{base, production, local}) {
+		t.Errorf("ProfilePaths() = %v, want local merged last", paths)
+	}
+
+	if _, err := ProfilePaths(base, "staging"); err == nil {
+		t.Error("ProfilePaths() error = nil, want the missing staging profile")
+	}
+}
+
+func TestLoadProfileMerge(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	writeFile(t, base, "name: app\ndb:\n  host: localhost\n  port: 5432\n  pool:\n    min: 1\n    max: 5\nhosts: [a, b, c]\n")
+	writeFile(t, filepath.Join(dir, "config.production.yaml"), "db:\n  host: prod-db\n  pool:\n    max: 50\nhosts: [p]\n")
+	writeFile(t, filepath.Join(dir, "config.local.yaml"), "db:\n  port: 6543\n")
+
+	cfg, err := LoadYAMLDocument[layeredConfig](base, WithProfile("production"), WithLookupEnv(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "app" || cfg.DB.Host != "prod-db" || cfg.DB.Port != 6543 {
+		t.Errorf("LoadYAMLDocument() = %+v, want name from base, host from production and port from local", *cfg)
+	}
+	if cfg.DB.Pool.Min != 1 || cfg.DB.Pool.Max != 50 {
+		t.Errorf("Pool = %+v, want min from base and max from production", cfg.DB.Pool)
+	}
+	if !slices.Equal(cfg.Hosts, []string{"p"}) {
+		t.Errorf("Hosts = %v, want the production list to replace the base list", cfg.Hosts)
+	}
+}
+
+func TestLoadProfileUnknownKey(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	production := filepath.Join(dir, "config.production.yaml")
+	writeFile(t, base, "name: app\n")
+	writeFile(t, production, "db:\n  host: prod-db\n  hots: typo\n")
+
+	_, err := LoadYAMLDocument[layeredConfig](base, WithProfile("production"), WithLookupEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), production+": line 3: field hots not found") {
+		t.Errorf("LoadYAMLDocument() error = %v, want the production file and line", err)
+	}
+}
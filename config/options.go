@@ -0,0 +1,91 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"time"
+)
+
+type (
+	Option      func(*loadOptions)
+	loadOptions struct {
+		lookupEnv LookupEnvFunc
+		profile   string
+		validate  bool
+		format    Format
+		flags     *flag.FlagSet
+
+		provenance *Provenance
+
+		pollInterval time.Duration
+	}
+)
+
+// WithLookupEnv replaces os.LookupEnv as the source for envconfig overrides.
+// Passing nil disables the env overlay entirely
+func WithLookupEnv(fn LookupEnvFunc) Option {
+	return func(opts *loadOptions) {
+		opts.lookupEnv = fn
+	}
+}
+
+// WithProfile merges the profile overlay, e.g. config.production.yaml, over the base document.
+// Only used when loading from a path
+func WithProfile(profile string) Option {
+	return func(opts *loadOptions) {
+		opts.profile = profile
+	}
+}
+
+// WithFlags applies the flags defined by BindFlags that were set on fs, after every other source
+func WithFlags(fs *flag.FlagSet) Option {
+	return func(opts *loadOptions) {
+		opts.flags = fs
+	}
+}
+
+// WithFormat overrides the format detected from the file extension, GetConfig defaults to YAML
+func WithFormat(format Format) Option {
+	return func(opts *loadOptions) {
+		opts.format = format
+	}
+}
+
+// WithProvenance fills p with the source of every value as the config is loaded
+func WithProvenance(p *Provenance) Option {
+	return func(opts *loadOptions) {
+		opts.provenance = p
+	}
+}
+
+// WithValidation runs Validate on the loaded config and fails the load if anything is invalid
+func WithValidation() Option {
+	return func(opts *loadOptions) {
+		opts.validate = true
+	}
+}
+
+// WithPollInterval sets how often a Watcher checks its files for changes,
+// an interval <= 0 keeps the default of 5s
+func WithPollInterval(interval time.Duration) Option {
+	return func(opts *loadOptions) {
+		if interval <= 0 {
+			return
+		}
+		opts.pollInterval = interval
+	}
+}
+
+func newOptions(options ...Option) loadOptions {
+	// default
+	opts := loadOptions{
+		lookupEnv:    os.LookupEnv,
+		pollInterval: 5 * time.Second,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return opts
+}
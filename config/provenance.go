@@ -0,0 +1,128 @@
+package config
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+type SourceKind string
+
+const (
+	SourceDefault SourceKind = "default"
+	SourceFile    SourceKind = "file"
+	SourceOverlay SourceKind = "overlay"
+	SourceEnv     SourceKind = "env"
+	SourceFlag    SourceKind = "flag"
+)
+
+// Source is where a value was last set from. Name is the file path, env var or flag when there is one
+type Source struct {
+	Kind SourceKind
+	Name string
+}
+
+func (s Source) String() string {
+	switch {
+	case s.Kind == "":
+		return "unset"
+	case s.Name == "":
+		return string(s.Kind)
+	}
+	return string(s.Kind) + " " + s.Name
+}
+
+// Provenance records the source of every value set while loading, keyed by yaml path e.g. conn.port.
+// Pass one to WithProvenance and then to Describe or Dump
+type Provenance struct {
+	sources map[string]Source
+}
+
+func NewProvenance() *Provenance {
+	return &Provenance{sources: make(map[string]Source)}
+}
+
+// Source returns where the value at path came from
+func (p *Provenance) Source(path string) (Source, bool) {
+	if p == nil {
+		return Source{}, false
+	}
+	s, ok := p.sources[path]
+	return s, ok
+}
+
+func (p *Provenance) reset() {
+	if p == nil {
+		return
+	}
+	p.sources = make(map[string]Source)
+}
+
+func (p *Provenance) set(path string, s Source) {
+	if p == nil {
+		return
+	}
+	if p.sources == nil {
+		p.sources = make(map[string]Source)
+	}
+	p.sources[path] = s
+}
+
+// recordDefaults marks every leaf with a `default` tag, later sources overwrite it
+func (p *Provenance) recordDefaults(t reflect.Type) {
+	if p == nil {
+		return
+	}
+	for _, lf := range leafFields(t) {
+		if _, ok := lf.field.Tag.Lookup("default"); ok {
+			p.set(lf.path, Source{Kind: SourceDefault})
+		}
+	}
+}
+
+// recordNode marks every key present in a parsed document
+func (p *Provenance) recordNode(node *yaml.Node, path string, s Source) {
+	if p == nil || node == nil {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			p.recordNode(child, path, s)
+		}
+
+	case yaml.AliasNode:
+		p.recordNode(node.Alias, path, s)
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if isMergeKey(node.Content[i]) {
+				for _, m := range mergedMappings(node.Content[i+1]) {
+					p.recordNode(m, path, s)
+				}
+				continue
+			}
+
+			childPath := joinPath(path, node.Content[i].Value)
+			p.set(childPath, s)
+			p.recordNode(node.Content[i+1], childPath, s)
+		}
+	}
+}
+
+// recordEnv marks every leaf whose envconfig variable is set
+func (p *Provenance) recordEnv(t reflect.Type, lookup LookupEnvFunc) {
+	if p == nil || lookup == nil {
+		return
+	}
+	for _, lf := range leafFields(t) {
+		name := lf.field.Tag.Get("envconfig")
+		if name == "" || name == "-" {
+			continue
+		}
+		if _, ok := lookup(name); ok {
+			p.set(lf.path, Source{Kind: SourceEnv, Name: name})
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	// durationPattern matches the strings accepted by time.ParseDuration
+	durationPattern = `^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$`
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// JSONSchema generates a JSON Schema for T using the same names GetConfig decodes.
+// `default` and `validate` tags are carried over where JSON Schema has an equivalent.
+// Fields tagged `validate:"required"` are only required in the document when they
+// have no `default` or `envconfig` tag, as otherwise they can be supplied elsewhere
+func JSONSchema[T any]() ([]byte, error) {
+	t := reflect.TypeFor[T]()
+	schema, err := typeSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	schema["$schema"] = schemaDraft
+	if name := derefType(t).Name(); name != "" {
+		schema["title"] = name
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func typeSchema(t reflect.Type) (map[string]any, error) {
+	t = derefType(t)
+
+	switch {
+	case t == durationType:
+		return map[string]any{"type": "string", "pattern": durationPattern}, nil
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return map[string]any{"type": "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+
+	case reflect.Map:
+		values, err := typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+
+	case reflect.Interface:
+		return map[string]any{}, nil
+
+	case reflect.Struct:
+		return structSchema(t)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+func structSchema(t reflect.Type) (map[string]any, error) {
+	properties := make(map[string]any)
+	var required []string
+
+	var addFields func(t reflect.Type) error
+	addFields = func(t reflect.Type) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			_, flags, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if strings.Contains(flags, "inline") && !isLeaf(field.Type) {
+				if err := addFields(derefType(field.Type)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			name := yamlName(field)
+			if name == "" {
+				continue
+			}
+
+			schema, err := fieldSchema(field)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+			}
+			properties[name] = schema
+
+			if isRequired(field) {
+				required = append(required, name)
+			}
+		}
+		return nil
+	}
+	if err := addFields(t); err != nil {
+		return nil, err
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+		// mirrors yaml.Decoder.KnownFields
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+func fieldSchema(field reflect.StructField) (map[string]any, error) {
+	schema, err := typeSchema(field.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if def, ok := field.Tag.Lookup("default"); ok {
+		value, err := defaultValue(field.Type, def)
+		if err != nil {
+			return nil, fmt.Errorf("default %q cannot be parsed as %s: %w", def, field.Type, err)
+		}
+		// the default of a secret is still checked but never published
+		if !isSecret(field) {
+			schema["default"] = value
+		}
+	}
+
+	if name := field.Tag.Get("envconfig"); name != "" && name != "-" {
+		schema["description"] = "Can be overridden with the " + name + " environment variable"
+	}
+	if isSecret(field) {
+		schema["writeOnly"] = true
+	}
+
+	applyValidateTag(schema, derefType(field.Type), field.Tag.Get("validate"))
+
+	return schema, nil
+}
+
+// defaultValue converts a `default` tag into the value it would be encoded as
+func defaultValue(t reflect.Type, def string) (any, error) {
+	v := reflect.New(t).Elem()
+	if err := setFromString(v, def); err != nil {
+		return nil, err
+	}
+
+	if derefType(t) == durationType || reflect.PointerTo(derefType(t)).Implements(textUnmarshalerType) {
+		return def, nil
+	}
+	return v.Interface(), nil
+}
+
+// applyValidateTag translates the validator rules that JSON Schema can express, the rest are left to Validate
+func applyValidateTag(schema map[string]any, t reflect.Type, tag string) {
+	if tag == "" {
+		return
+	}
+
+	if t == durationType {
+		// durations are written as strings so numeric bounds do not apply
+		return
+	}
+
+	var (
+		minKey, maxKey string
+		numeric        bool
+	)
+	switch t.Kind() {
+	case reflect.String:
+		minKey, maxKey = "minLength", "maxLength"
+	case reflect.Slice, reflect.Array:
+		minKey, maxKey = "minItems", "maxItems"
+	case reflect.Map:
+		minKey, maxKey = "minProperties", "maxProperties"
+	default:
+		minKey, maxKey = "minimum", "maximum"
+		numeric = true
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			// anything after dive applies to elements
+			return
+		}
+
+		switch name {
+		case "gt", "gte", "min":
+			if n, ok := schemaNumber(param); ok {
+				key := minKey
+				if name == "gt" {
+					key = "exclusiveMinimum"
+					if !numeric {
+						key = minKey
+						n++
+					}
+				}
+				schema[key] = n
+			}
+		case "lt", "lte", "max":
+			if n, ok := schemaNumber(param); ok {
+				key := maxKey
+				if name == "lt" {
+					key = "exclusiveMaximum"
+					if !numeric {
+						key = maxKey
+						n--
+					}
+				}
+				schema[key] = n
+			}
+		case "len":
+			if n, ok := schemaNumber(param); ok {
+				if numeric {
+					schema["const"] = n
+				} else {
+					schema[minKey], schema[maxKey] = n, n
+				}
+			}
+		case "oneof":
+			var enum []any
+			for _, option := range strings.Fields(param) {
+				if value, err := defaultValue(t, option); err == nil {
+					enum = append(enum, value)
+				}
+			}
+			schema["enum"] = enum
+		case "email":
+			schema["format"] = "email"
+		case "url", "uri":
+			schema["format"] = "uri"
+		case "hostname", "hostname_rfc1123":
+			schema["format"] = "hostname"
+		case "ipv4":
+			schema["format"] = "ipv4"
+		case "ipv6":
+			schema["format"] = "ipv6"
+		case "uuid":
+			schema["format"] = "uuid"
+		}
+	}
+}
+
+func isRequired(field reflect.StructField) bool {
+	if _, ok := field.Tag.Lookup("default"); ok {
+		return false
+	}
+	if name := field.Tag.Get("envconfig"); name != "" && name != "-" {
+		return false
+	}
+
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if rule == "dive" {
+			return false
+		}
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaNumber(param string) (float64, bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	return n, err == nil
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type secretConfig struct {
+	User     string `yaml:"user" default:"postgres"`
+	Password string `yaml:"password" default:"pgpass" secret:"true"`
+}
+
+func TestJSONSchemaHidesSecretDefaults(t *testing.T) {
+	b, err := JSONSchema[secretConfig]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := schema.Properties["user"]["default"]; got != "postgres" {
+		t.Errorf("user default = %v, want postgres", got)
+	}
+	password := schema.Properties["password"]
+	if _, ok := password["default"]; ok {
+		t.Errorf("password schema = %v, want no default", password)
+	}
+	if password["writeOnly"] != true {
+		t.Errorf("password schema = %v, want writeOnly", password)
+	}
+}
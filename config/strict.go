@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// checkKnownFields mirrors yaml.Decoder.KnownFields for a parsed node.
+// Checking nodes rather than bytes keeps line numbers pointing at the original file
+// after interpolation and before documents are merged
+func checkKnownFields(node *yaml.Node, t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := checkKnownFields(child, t); err != nil {
+				return err
+			}
+		}
+
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			return checkKnownFields(node.Alias, t)
+		}
+
+	case yaml.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		for _, child := range node.Content {
+			if err := checkKnownFields(child, t.Elem()); err != nil {
+				return err
+			}
+		}
+
+	case yaml.MappingNode:
+		switch {
+		case t.Kind() == reflect.Map:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if isMergeKey(node.Content[i]) {
+					if err := checkMerged(node.Content[i+1], t); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := checkKnownFields(node.Content[i+1], t.Elem()); err != nil {
+					return err
+				}
+			}
+
+		case t.Kind() == reflect.Struct && !isLeaf(t):
+			fields := yamlFields(t)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				// the merged mappings are checked against the same struct
+				if isMergeKey(key) {
+					if err := checkMerged(node.Content[i+1], t); err != nil {
+						return err
+					}
+					continue
+				}
+
+				ft, ok := fields[key.Value]
+				if !ok {
+					return atLine(key, fmt.Errorf("field %s not found in type %s", key.Value, t))
+				}
+				if err := checkKnownFields(node.Content[i+1], ft); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkMerged checks the mappings of a merge key against the type they are merged into
+func checkMerged(value *yaml.Node, t reflect.Type) error {
+	for _, m := range mergedMappings(value) {
+		if err := checkKnownFields(m, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// atLine prefixes err with the line of node. Nodes re-encoded from TOML have no position
+// so their errors are returned as is rather than pointing at line 0
+func atLine(node *yaml.Node, err error) error {
+	if node.Line <= 0 {
+		return err
+	}
+	return fmt.Errorf("line %d: %w", node.Line, err)
+}
+
+// isMergeKey reports whether key is a YAML merge key, e.g. <<: *base
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge"
+}
+
+// mergedMappings lists the mappings of a merge key value, <<: *base or <<: [*a, *b]
+func mergedMappings(value *yaml.Node) []*yaml.Node {
+	if value.Kind == yaml.AliasNode {
+		value = value.Alias
+	}
+	if value == nil {
+		return nil
+	}
+	if value.Kind != yaml.SequenceNode {
+		return []*yaml.Node{value}
+	}
+	return value.Content
+}
+
+// yamlFields maps the keys yaml.v3 accepts for a struct to their types, following inline fields
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		_, flags, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if strings.Contains(flags, "inline") && field.Type.Kind() == reflect.Struct {
+			for name, ft := range yamlFields(field.Type) {
+				fields[name] = ft
+			}
+			continue
+		}
+
+		if name := yamlName(field); name != "" {
+			fields[name] = field.Type
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validator can be implemented by T, or any nested struct, for checks that `validate` tags cannot express
+type Validator interface {
+	Validate() error
+}
+
+// FieldError is a single validation failure, Path uses the yaml names e.g. conn.port
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (fe FieldError) Error() string {
+	if fe.Path == "" {
+		return fe.Message
+	}
+	return fe.Path + ": " + fe.Message
+}
+
+// ValidationError holds every failure found in a config rather than just the first
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, 0, len(ve.Fields))
+	for _, fe := range ve.Fields {
+		msgs = append(msgs, fe.Error())
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return yamlName(field)
+	})
+	return v
+}
+
+// Validate checks the `validate` tags on cfg and calls Validate() on every
+// struct that implements Validator. All failures are returned as a *ValidationError
+func Validate(cfg any) error {
+	var fields []FieldError
+
+	// validate tags only exist on structs, Validator is still honoured for other types
+	var err error
+	if reflect.Indirect(reflect.ValueOf(cfg)).Kind() == reflect.Struct {
+		err = validate.Struct(cfg)
+	}
+	var vErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &vErrs):
+		for _, fe := range vErrs {
+			fields = append(fields, FieldError{
+				Path:    trimRoot(fe.Namespace()),
+				Message: validationMessage(fe),
+			})
+		}
+	case err != nil:
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	fields = append(fields, callValidators(reflect.ValueOf(cfg), "")...)
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+func callValidators(v reflect.Value, path string) []FieldError {
+	var fields []FieldError
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+	} else if v.CanAddr() {
+		v = v.Addr()
+	}
+
+	if fn, ok := v.Interface().(Validator); ok {
+		if err := fn.Validate(); err != nil {
+			fields = append(fields, FieldError{Path: path, Message: err.Error()})
+		}
+	}
+
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct || isLeaf(v.Type()) {
+		return fields
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+		fields = append(fields, callValidators(v.Field(i), joinPath(path, name))...)
+	}
+
+	return fields
+}
+
+func validationMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	sized := false
+	switch fe.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		sized = true
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "gt", "gte", "lt", "lte", "min", "max", "len", "eq", "ne":
+		op := map[string]string{
+			"gt": ">", "gte": ">=", "lt": "<", "lte": "<=",
+			"min": ">=", "max": "<=", "len": "==", "eq": "==", "ne": "!=",
+		}[fe.Tag()]
+		if sized {
+			return fmt.Sprintf("length must be %s %s", op, param)
+		}
+		return fmt.Sprintf("must be %s %s", op, param)
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", param)
+	}
+
+	if param != "" {
+		return fmt.Sprintf("failed validation check: %s=%s", fe.Tag(), param)
+	}
+	return "failed validation check: " + fe.Tag()
+}
+
+// trimRoot removes the type name validator puts at the start of a namespace
+func trimRoot(namespace string) string {
+	if _, rest, ok := strings.Cut(namespace, "."); ok {
+		return rest
+	}
+	return namespace
+}
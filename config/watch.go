@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Watcher keeps the latest valid config loaded from a path.
+// The files, and the files they reference with ${file:/path}, are polled for changes and re-read on SIGHUP.
+// A reload that fails to load or validate is logged with slog.Default and the previous snapshot is kept
+type Watcher[T any] struct {
+	path    string
+	options []Option
+	opts    loadOptions
+
+	current  atomic.Pointer[T]
+	checksum []byte
+
+	mu          sync.Mutex
+	subscribers []func(old, new *T)
+}
+
+// NewWatcher loads the config at path and keeps it up to date until ctx is done.
+// The initial load must succeed. WithProvenance is not supported as reloads happen in the background
+func NewWatcher[T any](ctx context.Context, path string, options ...Option) (*Watcher[T], error) {
+	// a reload is only ever applied once it is known to be valid, clip so the caller's slice is not written to
+	options = append(slices.Clip(options), WithValidation())
+
+	w := &Watcher[T]{
+		path:    path,
+		options: options,
+		opts:    newOptions(options...),
+	}
+	if w.opts.provenance != nil {
+		return nil, errors.New("WithProvenance cannot be used with a Watcher")
+	}
+
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+
+	go w.run(ctx)
+
+	return w, nil
+}
+
+// Current returns the latest valid snapshot. It must be treated as read only
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called after every successful reload.
+// fn runs synchronously within the reload so it must not call back into the Watcher
+func (w *Watcher[T]) Subscribe(fn func(old, new *T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload re-reads the config straight away. It is safe to call alongside the background watcher
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	checksum, err := w.fileChecksum()
+	if err != nil {
+		return err
+	}
+
+	// remember the attempt even if it fails so a broken file is only reported once
+	w.checksum = checksum
+
+	cfg, err := LoadYAMLDocument[T](w.path, w.options...)
+	if err != nil {
+		return err
+	}
+
+	old := w.current.Swap(cfg)
+	if old == nil {
+		return nil
+	}
+
+	for _, fn := range w.subscribers {
+		fn(old, cfg)
+	}
+
+	return nil
+}
+
+func (w *Watcher[T]) run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(w.opts.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-hup:
+			w.reload(ctx, "signal")
+
+		case <-ticker.C:
+			if !w.changed(ctx) {
+				continue
+			}
+			w.reload(ctx, "file changed")
+		}
+	}
+}
+
+func (w *Watcher[T]) reload(ctx context.Context, reason string) {
+	if err := w.Reload(); err != nil {
+		slog.ErrorContext(ctx, "config reload failed, keeping previous config",
+			slog.String("path", w.path),
+			slog.String("reason", reason),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	slog.InfoContext(ctx, "config reloaded", slog.String("path", w.path), slog.String("reason", reason))
+}
+
+func (w *Watcher[T]) changed(ctx context.Context) bool {
+	checksum, err := w.fileChecksum()
+	if err != nil {
+		slog.ErrorContext(ctx, "config watch failed", slog.String("path", w.path), slog.Any("error", err))
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return !bytes.Equal(checksum, w.checksum)
+}
+
+// fileChecksum hashes every file that makes up the config, including ${file:/path} references
+// so a rotated secret is picked up. Content is compared rather than mod times as mounted
+// config maps and secrets are swapped via symlinks
+func (w *Watcher[T]) fileChecksum() ([]byte, error) {
+	paths, err := ProfilePaths(w.path, w.opts.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	var refs []string
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+		fmt.Fprintf(h, "%s:%d:", path, len(b))
+		h.Write(b)
+		refs = append(refs, fileReferences(string(b))...)
+	}
+
+	for _, path := range refs {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			// a missing reference may have a fallback, its appearance is a change too
+			fmt.Fprintf(h, "%s:missing:", path)
+			continue
+		}
+		fmt.Fprintf(h, "%s:%d:", path, len(b))
+		h.Write(b)
+	}
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type watchConfig struct {
+	Name string `yaml:"name" validate:"required"`
+	Port int    `yaml:"port" validate:"gt=0"`
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitFor polls cond until it holds or the deadline passes
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "name: a\nport: 1\n")
+
+	w, err := NewWatcher[watchConfig](t.Context(), path, WithLookupEnv(nil), WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var calls [][2]watchConfig
+	w.Subscribe(func(old, new *watchConfig) {
+		mu.Lock()
+		calls = append(calls, [2]watchConfig{*old, *new})
+		mu.Unlock()
+	})
+
+	writeFile(t, path, "name: b\nport: 2\n")
+	waitFor(t, func() bool { return w.Current().Name == "b" })
+
+	mu.Lock()
+	if len(calls) != 1 || calls[0][0].Name != "a" || calls[0][1].Name != "b" {
+		t.Errorf("subscriber calls = %+v, want one call from a to b", calls)
+	}
+	mu.Unlock()
+}
+
+func TestWatcherInvalidReloadKeepsSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "name: a\nport: 1\n")
+
+	w, err := NewWatcher[watchConfig](t.Context(), path, WithLookupEnv(nil), WithPollInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := w.Current()
+
+	for name, content := range map[string]string{
+		"validation": "name: a\nport: 0\n",
+		"unknown":    "name: a\nport: 1\nbogus: true\n",
+		"malformed":  "name: [\n",
+	} {
+		writeFile(t, path, content)
+		if err := w.Reload(); err == nil {
+			t.Errorf("%s: Reload() error = nil, want an error", name)
+		}
+		if got := w.Current(); got != before {
+			t.Errorf("%s: Current() = %+v, want the previous snapshot %+v", name, got, before)
+		}
+	}
+}
+
+func TestWatcherInitialLoadMustSucceed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "port: 1\n")
+
+	if _, err := NewWatcher[watchConfig](t.Context(), path, WithLookupEnv(nil)); err == nil {
+		t.Fatal("NewWatcher() error = nil, want a validation error")
+	}
+}
+
+func TestWithPollIntervalIgnoresNonPositive(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if got := newOptions(WithPollInterval(interval)).pollInterval; got != 5*time.Second {
+			t.Errorf("WithPollInterval(%v) = %v, want the 5s default", interval, got)
+		}
+	}
+}
+
+func TestWatcherReloadsFileReferences(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "name")
+	writeFile(t, secret, "first\n")
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "name: ${file:"+secret+"}\nport: 1\n")
+
+	w, err := NewWatcher[watchConfig](t.Context(), path, WithLookupEnv(nil), WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Current().Name; got != "first" {
+		t.Fatalf("Current().Name = %q, want first", got)
+	}
+
+	// only the referenced file changes, as when a mounted secret is rotated
+	writeFile(t, secret, "second\n")
+	waitFor(t, func() bool { return w.Current().Name == "second" })
+}
+
+func TestNewWatcherOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "name: a\nport: 1\n")
+
+	options := make([]Option, 1, 2)
+	options[0] = WithLookupEnv(nil)
+	if _, err := NewWatcher[watchConfig](t.Context(), path, options...); err != nil {
+		t.Fatal(err)
+	}
+	if spare := options[:2][1]; spare != nil {
+		t.Error("NewWatcher() wrote into the caller's options")
+	}
+
+	if _, err := NewWatcher[watchConfig](t.Context(), path, WithLookupEnv(nil), WithProvenance(NewProvenance())); err == nil {
+		t.Error("NewWatcher() with WithProvenance error = nil, want an error")
+	}
+}
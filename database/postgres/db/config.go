@@ -21,17 +21,18 @@ type DBConfig struct {
 
 // ConnectionConfig contains all parameters needed to connect to the database
 type ConnectionConfig struct {
-	Username string `yaml:"user" envconfig:"DB_USER" default:"pguser"`
-	Password string `yaml:"pass" envconfig:"DB_PASS" default:"pgpass"`
-	Name     string `yaml:"name" envconfig:"DB_NAME" default:"postgres"`
-	Host     string `yaml:"host" envconfig:"DB_HOST" default:"127.0.0.1"`
-	Port     int    `yaml:"port" envconfig:"DB_PORT" default:"5432"`
+	Username string `yaml:"user" envconfig:"DB_USER" default:"pguser" validate:"required"`
+	Password string `yaml:"pass" envconfig:"DB_PASS" default:"pgpass" secret:"true"`
+	Name     string `yaml:"name" envconfig:"DB_NAME" default:"postgres" validate:"required"`
+	Host     string `yaml:"host" envconfig:"DB_HOST" default:"127.0.0.1" validate:"required"`
+	Port     int    `yaml:"port" envconfig:"DB_PORT" default:"5432" validate:"gt=0,lte=65535"`
 	SSL      bool   `yaml:"useSSL" envconfig:"USE_SSL_DB" default:"false"`
 }
 
 func (c *ConnectionConfig) LogValue() slog.Value {
-	noPass := c
-	c.Password = "*******"
+	// copy so the receiver keeps its password
+	noPass := *c
+	noPass.Password = "*******"
 	return slog.GroupValue(
 		slog.String("username", c.Username),
 		slog.String("password", "*********"),
@@ -39,15 +40,15 @@ func (c *ConnectionConfig) LogValue() slog.Value {
 		slog.String("host", c.Host),
 		slog.Int("port", c.Port),
 		slog.Bool("SSL", c.SSL),
-		slog.String("URL", URLForConfig(*noPass)),
+		slog.String("URL", URLForConfig(noPass)),
 	)
 }
 
 // SQLConfig contains all parameters needed congigure connections to the database
 type SQLConfig struct {
-	MaxOpenConns    int           `yaml:"maxOpenConns" envconfig:"DB_MAX_OPEN_CONNS" default:"5"`             // strconv.Atoi
-	MaxIdleConns    int           `yaml:"idleOpenConns" envconfig:"DB_IDLE_OPEN_CONNS" default:"3"`           // strconv.Atoi
-	ConnMaxLifetime time.Duration `yaml:"maxLifetimeConns" envconfig:"DB_MAX_LIFETIME_CONNS" default:"1800s"` // time.Duration(maxSec) * time.Second
+	MaxOpenConns    int           `yaml:"maxOpenConns" envconfig:"DB_MAX_OPEN_CONNS" default:"5" validate:"gte=0"`             // strconv.Atoi
+	MaxIdleConns    int           `yaml:"idleOpenConns" envconfig:"DB_IDLE_OPEN_CONNS" default:"3" validate:"gte=0"`           // strconv.Atoi
+	ConnMaxLifetime time.Duration `yaml:"maxLifetimeConns" envconfig:"DB_MAX_LIFETIME_CONNS" default:"1800s" validate:"gte=0"` // time.Duration(maxSec) * time.Second
 }
 
 // config to url string
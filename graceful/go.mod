@@ -2,9 +2,10 @@ module github.com/sgrumley/lib/graceful
 
 go 1.23.1
 
+require github.com/sgrumley/lib/logger v0.0.0-20250925114205-9456ed8e7401
+
 require (
 	github.com/golang-cz/devslog v0.0.15 // indirect
 	github.com/lmittmann/tint v1.1.2 // indirect
 	github.com/phsym/console-slog v0.3.1 // indirect
-	github.com/sgrumley/lib/logger v0.0.0-20250925114205-9456ed8e7401 // indirect
 )
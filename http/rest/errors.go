@@ -2,6 +2,7 @@ package rest
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -76,6 +77,27 @@ func (er Error) Error() string {
 	return sb.String()
 }
 
+// Unwrap exposes the internal error so errors.Is/As and logger.Err can follow the chain
+func (er Error) Unwrap() error {
+	return er.Err
+}
+
+// LogAttrs exposes the fields worth querying logs by, it is picked up by logger.Err
+func (er Error) LogAttrs() []slog.Attr {
+	attrs := []slog.Attr{slog.Int("status", er.Status)}
+	if er.Code != "" {
+		attrs = append(attrs, slog.String("code", er.Code))
+	}
+	if len(er.Fields) > 0 {
+		fields := make([]any, 0, len(er.Fields))
+		for _, fe := range er.Fields {
+			fields = append(fields, slog.String(fe.Field, fe.Description))
+		}
+		attrs = append(attrs, slog.Group("fields", fields...))
+	}
+	return attrs
+}
+
 func (er Error) GetData() (int, string, string, []FieldError) {
 	return er.Status, er.Code, er.Description, er.Fields
 }
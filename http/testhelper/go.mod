@@ -1,3 +1,5 @@
 module github.com/sgrumley/lib/http/testhelper
 
 go 1.23.1
+
+require github.com/go-chi/chi/v5 v5.3.2
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncOptions configures NewAsyncHandler
+type AsyncOptions struct {
+	// BufferSize is the number of records queued before the handler blocks or drops, default 1024
+	BufferSize int
+	// DropOldest overwrites the oldest queued record when the buffer is full instead of blocking the caller
+	DropOldest bool
+}
+
+type asyncEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// asyncQueue is a bounded ring buffer shared by every handler derived through WithAttrs and WithGroup
+type asyncQueue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	entries  []asyncEntry
+	head     int
+	count    int
+	busy     bool
+	closed   bool
+	drained  chan struct{}
+	done     chan struct{}
+
+	dropOldest bool
+	dropped    atomic.Uint64
+}
+
+// AsyncHandler hands records to a background goroutine so slow outputs do not block the caller.
+// Close must be called on shutdown so queued records are written, records handled after Close
+// are written synchronously
+type AsyncHandler struct {
+	next  slog.Handler
+	queue *asyncQueue
+}
+
+func NewAsyncHandler(next slog.Handler, opts AsyncOptions) *AsyncHandler {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+
+	q := &asyncQueue{
+		entries:    make([]asyncEntry, opts.BufferSize),
+		drained:    make(chan struct{}),
+		done:       make(chan struct{}),
+		dropOldest: opts.DropOldest,
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+
+	go q.run()
+
+	return &AsyncHandler{next: next, queue: q}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	queued := h.queue.push(asyncEntry{
+		// the record outlives the caller, keep its values but not its cancellation
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.next,
+		record:  r.Clone(),
+	})
+	if !queued {
+		// slog drops handler errors, so a closed queue would lose e.g. the final shutdown lines
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), queue: h.queue}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), queue: h.queue}
+}
+
+// Dropped is the number of records overwritten because the buffer was full
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.queue.dropped.Load()
+}
+
+// Flush waits until every queued record has been written or ctx is done
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	q := h.queue
+	q.mu.Lock()
+	if q.count == 0 && !q.busy {
+		q.mu.Unlock()
+		return nil
+	}
+	drained := q.drained
+	q.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting records and flushes the queue. It matches graceful.ShutdownHandler
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	q := h.queue
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		q.notEmpty.Broadcast()
+		q.notFull.Broadcast()
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// push reports false once the queue is closed
+func (q *asyncQueue) push(e asyncEntry) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.count == len(q.entries) && !q.dropOldest && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		return false
+	}
+
+	if q.count == len(q.entries) {
+		q.head = (q.head + 1) % len(q.entries)
+		q.count--
+		q.dropped.Add(1)
+	}
+
+	q.entries[(q.head+q.count)%len(q.entries)] = e
+	q.count++
+	q.notEmpty.Signal()
+	return true
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+
+	for {
+		q.mu.Lock()
+		for q.count == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if q.count == 0 && q.closed {
+			q.mu.Unlock()
+			return
+		}
+
+		e := q.entries[q.head]
+		q.entries[q.head] = asyncEntry{}
+		q.head = (q.head + 1) % len(q.entries)
+		q.count--
+		q.busy = true
+		q.notFull.Signal()
+		q.mu.Unlock()
+
+		// errors from the output have nowhere to go once the caller has returned
+		_ = e.handler.Handle(e.ctx, e.record)
+
+		q.mu.Lock()
+		q.busy = false
+		if q.count == 0 {
+			close(q.drained)
+			q.drained = make(chan struct{})
+		}
+		q.mu.Unlock()
+	}
+}
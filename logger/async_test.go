@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateHandler blocks the first Handle until release is closed, so the async queue can be filled
+type gateHandler struct {
+	*RecordingHandler
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGateHandler() *gateHandler {
+	return &gateHandler{
+		RecordingHandler: NewRecordingHandler(nil),
+		started:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+}
+
+func (h *gateHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.once.Do(func() {
+		close(h.started)
+		<-h.release
+	})
+	return h.RecordingHandler.Handle(ctx, r)
+}
+
+func messages(entries []Entry) []string {
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	gate := newGateHandler()
+	h := NewAsyncHandler(gate, AsyncOptions{BufferSize: 2, DropOldest: true})
+	l := slog.New(h)
+
+	l.Info("r0")
+	<-gate.started // the worker holds r0, the buffer is empty
+	for _, msg := range []string{"r1", "r2", "r3", "r4"} {
+		l.Info(msg)
+	}
+	if got := h.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+
+	close(gate.release)
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := messages(gate.Records())
+	want := []string{"r0", "r3", "r4"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("written %v, want %v", got, want)
+	}
+}
+
+func TestAsyncBlocksWhenFull(t *testing.T) {
+	rec := NewRecordingHandler(nil)
+	h := NewAsyncHandler(rec, AsyncOptions{BufferSize: 1})
+	l := slog.New(h)
+
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				l.Info("msg", "g", g, "i", i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := h.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(rec.Records()); n != 200 {
+		t.Errorf("written %d records, want 200", n)
+	}
+	if got := h.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0 without DropOldest", got)
+	}
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAsyncFlushAndHandleAfterClose(t *testing.T) {
+	rec := NewRecordingHandler(nil)
+	h := NewAsyncHandler(rec, AsyncOptions{})
+	l := slog.New(h)
+	l.Info("before")
+
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := h.Flush(ctx); err != nil {
+		t.Errorf("Flush() after Close error = %v", err)
+	}
+
+	// records after Close are written synchronously rather than lost
+	l.Info("after")
+	if got := messages(rec.Records()); len(got) != 2 || got[0] != "before" || got[1] != "after" {
+		t.Errorf("written %v, want [before after]", got)
+	}
+}
+
+func TestAsyncCloseHonoursContext(t *testing.T) {
+	gate := newGateHandler()
+	h := NewAsyncHandler(gate, AsyncOptions{})
+	slog.New(h).Info("stuck")
+	<-gate.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := h.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	close(gate.release)
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
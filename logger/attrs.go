@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+type attrsKey struct{}
+
+// WithAttrs stores attributes in ctx, they are added to every record logged with that ctx
+// by a handler wrapped with NewContextHandler. args are handled the same as slog.Logger.With
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	var r slog.Record
+	r.Add(args...)
+
+	existing := AttrsFromContext(ctx)
+	attrs := make([]slog.Attr, 0, len(existing)+r.NumAttrs())
+	attrs = append(attrs, existing...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+type contextHandler struct {
+	next slog.Handler
+	// root is next before the first group was opened and scope replays what was added since,
+	// so attributes from the context can be added outside any group
+	root  slog.Handler
+	scope []handlerScope
+}
+
+// handlerScope is a WithGroup call when group is set, a WithAttrs call otherwise
+type handlerScope struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewContextHandler adds the attributes stored with WithAttrs to each record at the top level,
+// outside any group opened with WithGroup. NewLogger always includes it, so slog.InfoContext(ctx, ...)
+// picks them up too
+func NewContextHandler(next slog.Handler) slog.Handler {
+	return &contextHandler{next: next, root: next}
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := AttrsFromContext(ctx)
+	if len(attrs) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	if len(h.scope) == 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+		return h.next.Handle(ctx, r)
+	}
+
+	// a group is open, rebuild the handler with the attributes added before it
+	next := h.root.WithAttrs(attrs)
+	for _, s := range h.scope {
+		if s.group != "" {
+			next = next.WithGroup(s.group)
+		} else {
+			next = next.WithAttrs(s.attrs)
+		}
+	}
+	return next.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.scope) == 0 {
+		next := h.next.WithAttrs(attrs)
+		return &contextHandler{next: next, root: next}
+	}
+	return &contextHandler{
+		next:  h.next.WithAttrs(attrs),
+		root:  h.root,
+		scope: append(slices.Clip(h.scope), handlerScope{attrs: attrs}),
+	}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &contextHandler{
+		next:  h.next.WithGroup(name),
+		root:  h.root,
+		scope: append(slices.Clip(h.scope), handlerScope{group: name}),
+	}
+}
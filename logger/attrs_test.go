@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// decodeLines parses each JSON record written to buf
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal(line, &m); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		records = append(records, m)
+	}
+	return records
+}
+
+func TestContextHandlerAttrsOutsideGroups(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+	ctx := WithAttrs(context.Background(), "request_id", "r1")
+
+	l.With("a", 1).WithGroup("http").With("b", 2).InfoContext(ctx, "grouped", "c", 3)
+	l.InfoContext(ctx, "flat")
+
+	records := decodeLines(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	grouped := records[0]
+	if grouped["request_id"] != "r1" || grouped["a"] != 1.0 {
+		t.Errorf("grouped record = %v, want request_id and a at the top level", grouped)
+	}
+	http, _ := grouped["http"].(map[string]any)
+	if http["b"] != 2.0 || http["c"] != 3.0 || http["request_id"] != nil {
+		t.Errorf("http group = %v, want b and c only", http)
+	}
+
+	if records[1]["request_id"] != "r1" {
+		t.Errorf("flat record = %v, want request_id", records[1])
+	}
+}
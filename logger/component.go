@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ComponentKey is the attribute Named adds to each record
+const ComponentKey = "component"
+
+// componentLevels holds the per component minimum levels of a Logger and every logger derived from it
+type componentLevels struct {
+	global *slog.LevelVar
+	// sinks are the levels given explicitly to WithSinks, the root logger lets through what any of them accepts
+	sinks []slog.Leveler
+
+	mu     sync.RWMutex
+	levels map[string]slog.Level
+	// min is the lowest component level so the output handlers can let those records through
+	min atomic.Int64
+}
+
+func newComponentLevels(global *slog.LevelVar, levels map[string]Level, sinks []slog.Leveler) *componentLevels {
+	c := &componentLevels{
+		global: global,
+		sinks:  sinks,
+		levels: make(map[string]slog.Level, len(levels)),
+	}
+	for name, level := range levels {
+		c.levels[name] = slog.Level(level)
+	}
+	c.updateMin()
+	return c
+}
+
+func (c *componentLevels) set(name string, level slog.Level) {
+	c.mu.Lock()
+	c.levels[name] = level
+	c.updateMin()
+	c.mu.Unlock()
+}
+
+func (c *componentLevels) unset(name string) {
+	c.mu.Lock()
+	delete(c.levels, name)
+	c.updateMin()
+	c.mu.Unlock()
+}
+
+func (c *componentLevels) snapshot() map[string]Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	levels := make(map[string]Level, len(c.levels))
+	for name, level := range c.levels {
+		levels[name] = Level(level)
+	}
+	return levels
+}
+
+// updateMin must be called with mu held or before c is shared
+func (c *componentLevels) updateMin() {
+	lowest := int64(math.MaxInt64)
+	for _, level := range c.levels {
+		lowest = min(lowest, int64(level))
+	}
+	c.min.Store(lowest)
+}
+
+// Level is the lowest of the global and component levels, it is used by the output handlers
+func (c *componentLevels) Level() slog.Level {
+	return slog.Level(min(int64(c.global.Level()), c.min.Load()))
+}
+
+// root is the lowest of the global and explicit sink levels
+func (c *componentLevels) root() slog.Level {
+	level := c.global.Level()
+	for _, sink := range c.sinks {
+		level = min(level, sink.Level())
+	}
+	return level
+}
+
+// lookup finds the level for name, falling back to its parents e.g. db.pool then db,
+// and then the root level
+func (c *componentLevels) lookup(name string) slog.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for name != "" {
+		if level, ok := c.levels[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return c.root()
+}
+
+// leveler returns the minimum level for the named component, an empty name is the root logger
+func (c *componentLevels) leveler(name string) slog.Leveler {
+	if name == "" {
+		if len(c.sinks) == 0 {
+			return c.global
+		}
+		return rootLeveler{levels: c}
+	}
+	return componentLeveler{levels: c, name: name}
+}
+
+type rootLeveler struct {
+	levels *componentLevels
+}
+
+func (l rootLeveler) Level() slog.Level {
+	return l.levels.root()
+}
+
+type componentLeveler struct {
+	levels *componentLevels
+	name   string
+}
+
+func (l componentLeveler) Level() slog.Level {
+	return l.levels.lookup(l.name)
+}
+
+// componentHandler is the outermost handler of a Logger. It applies the logger's own minimum level
+// as the output handlers are enabled down to the lowest component level
+type componentHandler struct {
+	next  slog.Handler
+	level slog.Leveler
+	// unnamed is next without the component attribute so a nested Named can replace it
+	unnamed slog.Handler
+}
+
+func newComponentHandler(next slog.Handler, level slog.Leveler) *componentHandler {
+	return &componentHandler{next: next, level: level, unnamed: next}
+}
+
+// named adds the component attribute with WithAttrs so it stays outside groups opened later
+func (h *componentHandler) named(name string, level slog.Leveler) *componentHandler {
+	return &componentHandler{
+		next:    h.unnamed.WithAttrs([]slog.Attr{slog.String(ComponentKey, name)}),
+		level:   level,
+		unnamed: h.unnamed,
+	}
+}
+
+func (h *componentHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+func (h *componentHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &componentHandler{next: h.next.WithAttrs(attrs), level: h.level, unnamed: h.unnamed.WithAttrs(attrs)}
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return &componentHandler{next: h.next.WithGroup(name), level: h.level, unnamed: h.unnamed.WithGroup(name)}
+}
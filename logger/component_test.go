@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNamedComponentAttr(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithFormat(HandlerJSON), WithSetDefault(false))
+
+	l.Named("db").WithGroup("q").Info("grouped", "n", 1)
+	l.Named("db").With("a", 1).Named("pool").Info("nested")
+
+	records := decodeLines(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	if records[0]["component"] != "db" {
+		t.Errorf("grouped record = %v, want component db at the top level", records[0])
+	}
+	if q, _ := records[0]["q"].(map[string]any); q["component"] != nil || q["n"] != 1.0 {
+		t.Errorf("q group = %v, want n only", q)
+	}
+
+	if records[1]["component"] != "db.pool" || records[1]["a"] != 1.0 {
+		t.Errorf("nested record = %v, want component db.pool and a", records[1])
+	}
+	if n := bytes.Count(buf.Bytes(), []byte(`"component"`)); n != 2 {
+		t.Errorf("component written %d times, want once per record", n)
+	}
+}
+
+func TestComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(
+		WithOutput(&buf),
+		WithFormat(HandlerJSON),
+		WithSetDefault(false),
+		WithComponentLevels(map[string]Level{"db": LevelDebug, "http": LevelWarn}),
+	)
+
+	l.Debug("root")
+	l.Named("db").Debug("db")
+	l.Named("db").Named("pool").Debug("pool")
+	l.Named("http").Info("http")
+
+	l.SetComponentLevel("db.pool", LevelError)
+	l.Named("db").Named("pool").Warn("pool after")
+
+	var msgs []any
+	for _, r := range decodeLines(t, &buf) {
+		msgs = append(msgs, r["msg"])
+	}
+	if len(msgs) != 2 || msgs[0] != "db" || msgs[1] != "pool" {
+		t.Errorf("logged %v, want [db pool]", msgs)
+	}
+}
+
+func TestSinkLevels(t *testing.T) {
+	var debug, info bytes.Buffer
+	l := NewLogger(
+		WithSinks(
+			Sink{Format: HandlerText, Output: &debug, Level: LevelDebug},
+			Sink{Format: HandlerJSON, Output: &info, Level: LevelInfo},
+		),
+		WithSetDefault(false),
+	)
+
+	l.Debug("verbose")
+	l.Info("normal")
+	l.Named("db").Debug("db verbose")
+
+	if got := bytes.Count(debug.Bytes(), []byte("\n")); got != 3 || !bytes.Contains(debug.Bytes(), []byte("msg=verbose")) {
+		t.Errorf("debug sink = %q, want the 3 records", debug.String())
+	}
+	if records := decodeLines(t, &info); len(records) != 1 || records[0]["msg"] != "normal" {
+		t.Errorf("info sink = %v, want the info record only", records)
+	}
+
+	// the sink level still applies when the logger's level is raised
+	l.SetLevel(LevelError)
+	l.Debug("still verbose")
+	if !bytes.Contains(debug.Bytes(), []byte("msg=\"still verbose\"")) {
+		t.Errorf("debug sink = %q, want the debug record after SetLevel", debug.String())
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"runtime"
+	"time"
 )
 
 type ctxKey struct{}
@@ -17,27 +19,58 @@ func LoggerFromContext(ctx context.Context) (*slog.Logger, bool) {
 	return logger, ok
 }
 
+// FromContext returns the logger added with AddLoggerContext, falling back to slog.Default.
+// NewLogger installs itself as the default so the fallback is the configured logger when there is one
 func FromContext(ctx context.Context) *slog.Logger {
 	logger, ok := LoggerFromContext(ctx)
 	if !ok {
-		return NewLogger().Logger
+		return slog.Default()
 	}
 	return logger
 }
 
-func Debug(ctx context.Context, msg string, attrs ...any) {
-	FromContext(ctx).Debug(msg, attrs...)
+func Debug(ctx context.Context, msg string, args ...any) {
+	log(ctx, slog.LevelDebug, msg, args...)
 }
 
-func Info(ctx context.Context, msg string, attrs ...any) {
-	FromContext(ctx).Info(msg, attrs...)
+func Info(ctx context.Context, msg string, args ...any) {
+	log(ctx, slog.LevelInfo, msg, args...)
 }
 
-func Error(ctx context.Context, msg string, err error) {
-	FromContext(ctx).Error(msg, slog.Any("error", err))
+func Warn(ctx context.Context, msg string, args ...any) {
+	log(ctx, slog.LevelWarn, msg, args...)
 }
 
-func Fatal(ctx context.Context, msg string, err error) {
-	Error(ctx, msg, err)
+// Error logs err as a structured attribute, see Err, followed by args as in slog.Logger.Error
+func Error(ctx context.Context, msg string, err error, args ...any) {
+	log(ctx, slog.LevelError, msg, errorArgs(err, args)...)
+}
+
+// Fatal logs at LevelError then exits. The default logger installed by NewLogger is closed first so its
+// async queue is written, a logger added with AddLoggerContext is not, use its (*Logger).Fatal instead
+func Fatal(ctx context.Context, msg string, err error, args ...any) {
+	log(ctx, slog.LevelError, msg, errorArgs(err, args)...)
+	if l := defaultLogger.Load(); l != nil {
+		_ = l.Close(context.WithoutCancel(ctx))
+	}
 	os.Exit(1)
 }
+
+func log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	logCaller(ctx, FromContext(ctx), 2, level, msg, args...) // skip [log, Info]
+}
+
+// logCaller is the equivalent of (*slog.Logger).Log with ctx passed through to the handler.
+// The record is built here so AddSource reports the caller rather than this package,
+// skip is the number of frames between the caller and logCaller
+func logCaller(ctx context.Context, l *slog.Logger, skip int, level slog.Level, msg string, args ...any) {
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:]) // skip [Callers, logCaller] as well
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = l.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RepeatedKey is the attribute holding how many duplicates were collapsed into a record
+const RepeatedKey = "repeated"
+
+// DedupOptions configures NewDedupHandler
+type DedupOptions struct {
+	// Window is how long identical records are collapsed for after the first one, default 5s
+	Window time.Duration
+}
+
+type dedupEntry struct {
+	next     slog.Handler
+	record   slog.Record
+	repeated int
+	timer    *time.Timer
+}
+
+// dedupState is shared by every handler derived through WithAttrs and WithGroup
+type dedupState struct {
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+	closed  bool
+}
+
+// DedupHandler collapses identical records, see NewDedupHandler
+type DedupHandler struct {
+	next  slog.Handler
+	opts  DedupOptions
+	state *dedupState
+	// scope identifies the attributes and groups added to this handler so they are part of the key
+	scope string
+}
+
+// NewDedupHandler passes the first of a run of identical records, same level, message and attributes,
+// straight to next and drops the duplicates that follow within the window. When the window closes
+// the record is logged again with a repeated attribute holding the number of duplicates dropped.
+// Call Close on shutdown to log the counts of the open windows
+func NewDedupHandler(next slog.Handler, opts DedupOptions) *DedupHandler {
+	if opts.Window <= 0 {
+		opts.Window = 5 * time.Second
+	}
+	return &DedupHandler{
+		next:  next,
+		opts:  opts,
+		state: &dedupState{entries: make(map[string]*dedupEntry)},
+	}
+}
+
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	key := h.key(r)
+
+	h.state.mu.Lock()
+	if h.state.closed {
+		h.state.mu.Unlock()
+		return h.next.Handle(ctx, r)
+	}
+	if e, ok := h.state.entries[key]; ok {
+		e.repeated++
+		h.state.mu.Unlock()
+		return nil
+	}
+
+	e := &dedupEntry{next: h.next, record: r.Clone()}
+	h.state.entries[key] = e
+	e.timer = time.AfterFunc(h.opts.Window, func() {
+		_ = h.expire(key, e)
+	})
+	h.state.mu.Unlock()
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	var sb strings.Builder
+	sb.WriteString(h.scope)
+	for _, a := range attrs {
+		writeDedupAttr(&sb, a)
+	}
+	return &DedupHandler{next: h.next.WithAttrs(attrs), opts: h.opts, state: h.state, scope: sb.String()}
+}
+
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &DedupHandler{next: h.next.WithGroup(name), opts: h.opts, state: h.state, scope: h.scope + name + "{"}
+}
+
+// Close logs the records of the open windows that had duplicates, records handled afterwards
+// are passed straight through
+func (h *DedupHandler) Close(ctx context.Context) error {
+	h.state.mu.Lock()
+	h.state.closed = true
+	entries := h.state.entries
+	h.state.entries = make(map[string]*dedupEntry)
+	for _, e := range entries {
+		e.timer.Stop()
+	}
+	h.state.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := e.emit(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *DedupHandler) expire(key string, e *dedupEntry) error {
+	h.state.mu.Lock()
+	// Close has already taken the entry
+	if h.state.entries[key] != e {
+		h.state.mu.Unlock()
+		return nil
+	}
+	delete(h.state.entries, key)
+	h.state.mu.Unlock()
+
+	// the record already has the context attributes, the original ctx may be cancelled by now
+	return e.emit(context.Background())
+}
+
+func (e *dedupEntry) emit(ctx context.Context) error {
+	if e.repeated == 0 {
+		return nil
+	}
+	r := e.record.Clone()
+	r.Time = time.Now()
+	r.AddAttrs(slog.Int(RepeatedKey, e.repeated))
+	return e.next.Handle(ctx, r)
+}
+
+func (h *DedupHandler) key(r slog.Record) string {
+	var sb strings.Builder
+	sb.WriteString(h.scope)
+	fmt.Fprintf(&sb, "|%d|%s|", r.Level, r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		writeDedupAttr(&sb, a)
+		return true
+	})
+	return sb.String()
+}
+
+func writeDedupAttr(sb *strings.Builder, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		fmt.Fprintf(sb, "%s=%+v;", a.Key, a.Value.Any())
+		return
+	}
+
+	sb.WriteString(a.Key)
+	sb.WriteByte('{')
+	for _, ga := range a.Value.Group() {
+		writeDedupAttr(sb, ga)
+	}
+	sb.WriteByte('}')
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitRecords polls until rec has n records or fails the test
+func waitRecords(t *testing.T, rec *RecordingHandler, n int) []Entry {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		entries := rec.Records()
+		if len(entries) >= n || time.Now().After(deadline) {
+			return entries
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDedupRepeatedCount(t *testing.T) {
+	rec := NewRecordingHandler(nil)
+	h := NewDedupHandler(rec, DedupOptions{Window: 30 * time.Millisecond})
+	defer h.Close(context.Background())
+	l := slog.New(h)
+
+	for range 5 {
+		l.Error("error pinging db", "attempt", "same")
+	}
+	l.Error("error pinging db", "attempt", "different")
+	l.Warn("error pinging db", "attempt", "same")
+	l.WithGroup("g").Error("error pinging db", "attempt", "same")
+
+	entries := waitRecords(t, rec, 5)
+	if len(entries) != 5 {
+		t.Fatalf("got %d records, want 4 firsts and 1 summary: %+v", len(entries), entries)
+	}
+	if !rec.Has(slog.LevelError, "attempt", "same", RepeatedKey, 4) {
+		t.Errorf("no repeated=4 record in %+v", entries)
+	}
+	if n := len(rec.Find(slog.LevelError, RepeatedKey, 4)); n != 1 {
+		t.Errorf("got %d summaries, want 1", n)
+	}
+
+	// a new window starts once the last one has closed
+	l.Error("error pinging db", "attempt", "same")
+	if entries := waitRecords(t, rec, 6); len(entries) != 6 {
+		t.Errorf("got %d records, want the first record of the new window", len(entries))
+	}
+}
+
+func TestDedupCloseFlushesOpenWindows(t *testing.T) {
+	rec := NewRecordingHandler(nil)
+	h := NewDedupHandler(rec, DedupOptions{Window: time.Hour})
+	l := slog.New(h)
+
+	for range 3 {
+		l.Info("retry")
+	}
+	l.Info("once")
+
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Has(slog.LevelInfo, slog.MessageKey, "retry", RepeatedKey, 2) {
+		t.Errorf("no repeated=2 record in %+v", rec.Records())
+	}
+	if n := len(rec.Records()); n != 3 {
+		t.Errorf("got %d records, want 3 without a summary for once", n)
+	}
+
+	// records after Close are passed straight through
+	l.Info("retry")
+	l.Info("retry")
+	if n := len(rec.Find(slog.LevelInfo, slog.MessageKey, "retry")); n != 4 {
+		t.Errorf("got %d retry records, want 4", n)
+	}
+}
+
+// TestDedupCloseRacesExpiry closes while windows expire so each summary must be written exactly once
+func TestDedupCloseRacesExpiry(t *testing.T) {
+	for range 50 {
+		rec := NewRecordingHandler(nil)
+		h := NewDedupHandler(rec, DedupOptions{Window: time.Millisecond})
+		l := slog.New(h)
+
+		var wg sync.WaitGroup
+		for g := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 20 {
+					l.Info("retry", "g", g)
+				}
+			}()
+		}
+		wg.Wait()
+		time.Sleep(time.Millisecond)
+		if err := h.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		// let any expiry that lost the race finish
+		time.Sleep(5 * time.Millisecond)
+
+		for g := range 4 {
+			total := 0
+			for _, e := range rec.Find(slog.LevelInfo, "g", g) {
+				// a summary repeats the first record of its window, which was already counted
+				if v, ok := e.Attr(RepeatedKey); ok {
+					total += int(v.Int64())
+				} else {
+					total++
+				}
+			}
+			if total != 20 {
+				t.Fatalf("g=%d accounted for %d records, want 20", g, total)
+			}
+		}
+	}
+}
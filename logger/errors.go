@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+const maxStackDepth = 32
+
+// AttrLogger can be implemented by errors to add their own attributes to Err, e.g. rest.Error adds
+// its status, code and fields. The first error in the chain that implements it is used
+type AttrLogger interface {
+	LogAttrs() []slog.Attr
+}
+
+type stackError struct {
+	err   error
+	stack []uintptr
+}
+
+// WithStack records the call stack where it is called, Err includes it when err is logged
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs) // skip [Callers, WithStack]
+	return &stackError{err: err, stack: pcs[:n]}
+}
+
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// Err renders err as a structured "error" group holding its message, type, the unwrapped
+// chain, a stack when one was captured with WithStack and any attributes from AttrLogger
+func Err(err error) slog.Attr {
+	return slog.Any("error", errorValue{err: err})
+}
+
+type errorValue struct {
+	err error
+}
+
+func (ev errorValue) LogValue() slog.Value {
+	if ev.err == nil {
+		return slog.StringValue("<nil>")
+	}
+
+	attrs := []slog.Attr{
+		slog.String("message", ev.err.Error()),
+		slog.String("type", errorType(ev.err)),
+	}
+
+	if chain := errorChain(ev.err); len(chain) > 1 {
+		attrs = append(attrs, slog.Any("chain", chain))
+	}
+
+	var se *stackError
+	if errors.As(ev.err, &se) {
+		attrs = append(attrs, slog.Any("stack", formatStack(se.stack)))
+	}
+
+	var al AttrLogger
+	if errors.As(ev.err, &al) {
+		attrs = append(attrs, al.LogAttrs()...)
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// errorChain lists every wrapped error depth first, including both sides of errors.Join.
+// The stack wrapper is skipped as it adds nothing to the message
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if _, ok := err.(*stackError); !ok {
+			chain = append(chain, errorType(err)+": "+err.Error())
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walk(e)
+			}
+		}
+	}
+	walk(err)
+
+	return chain
+}
+
+func errorType(err error) string {
+	if se, ok := err.(*stackError); ok {
+		return errorType(se.err)
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+func formatStack(pcs []uintptr) []string {
+	stack := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+	}
+	return stack
+}
@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// levelRequest is the PUT body, Duration is optional and reverts the change once it elapses e.g. "5m"
+type levelRequest struct {
+	Level    string `json:"level"`
+	Duration string `json:"duration,omitempty"`
+}
+
+type levelResponse struct {
+	Level    string     `json:"level"`
+	RevertTo string     `json:"revert_to,omitempty"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+type levelHandler struct {
+	level *slog.LevelVar
+
+	mu       sync.Mutex
+	timer    *time.Timer
+	revertTo slog.Level
+	revertAt time.Time
+}
+
+// NewLevelHandler serves the current level of lv on GET and changes it on PUT, e.g.
+//
+//	curl -X PUT -d '{"level":"debug","duration":"5m"}' localhost:8080/admin/log-level
+//
+// A temporary change reverts to the level that was set before the first temporary change,
+// a PUT without a duration makes the new level permanent
+func NewLevelHandler(lv *slog.LevelVar) http.Handler {
+	return &levelHandler{level: lv}
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.respond(w)
+
+	case http.MethodPut:
+		var req levelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeLevelError(w, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+			writeLevelError(w, err)
+			return
+		}
+
+		var duration time.Duration
+		if req.Duration != "" {
+			var err error
+			if duration, err = time.ParseDuration(req.Duration); err != nil || duration <= 0 {
+				writeLevelError(w, fmt.Errorf("invalid duration %q", req.Duration))
+				return
+			}
+		}
+
+		h.set(level, duration)
+		h.respond(w)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *levelHandler) set(level slog.Level, duration time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	} else {
+		h.revertTo = h.level.Level()
+	}
+
+	h.level.Set(level)
+	if duration == 0 {
+		return
+	}
+
+	h.revertAt = time.Now().Add(duration)
+	var timer *time.Timer
+	timer = time.AfterFunc(duration, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		// a newer request has replaced this timer
+		if h.timer != timer {
+			return
+		}
+		h.level.Set(h.revertTo)
+		h.timer = nil
+	})
+	h.timer = timer
+}
+
+func (h *levelHandler) respond(w http.ResponseWriter) {
+	h.mu.Lock()
+	res := levelResponse{Level: h.level.Level().String()}
+	if h.timer != nil {
+		revertAt := h.revertAt
+		res.RevertTo = h.revertTo.String()
+		res.RevertAt = &revertAt
+	}
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func writeLevelError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func putLevel(t *testing.T, h http.Handler, body string) levelResponse {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT %s status = %d, body %s", body, w.Code, w.Body)
+	}
+	var res levelResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// waitLevel polls until lv is want or fails the test
+func waitLevel(t *testing.T, lv *slog.LevelVar, want slog.Level) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for lv.Level() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("level = %v, want %v", lv.Level(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLevelHandlerTemporaryReverts(t *testing.T) {
+	lv := new(slog.LevelVar)
+	h := NewLevelHandler(lv)
+
+	res := putLevel(t, h, `{"level":"debug","duration":"20ms"}`)
+	if res.Level != "DEBUG" || res.RevertTo != "INFO" || res.RevertAt == nil {
+		t.Errorf("PUT response = %+v, want debug reverting to info", res)
+	}
+	waitLevel(t, lv, slog.LevelInfo)
+}
+
+func TestLevelHandlerKeepsOriginalRevertTo(t *testing.T) {
+	lv := new(slog.LevelVar)
+	h := NewLevelHandler(lv)
+
+	putLevel(t, h, `{"level":"debug","duration":"1h"}`)
+	res := putLevel(t, h, `{"level":"warn","duration":"20ms"}`)
+	if res.Level != "WARN" || res.RevertTo != "INFO" {
+		t.Errorf("PUT response = %+v, want warn reverting to the original info", res)
+	}
+	waitLevel(t, lv, slog.LevelInfo)
+}
+
+func TestLevelHandlerPermanentCancelsRevert(t *testing.T) {
+	lv := new(slog.LevelVar)
+	h := NewLevelHandler(lv)
+
+	putLevel(t, h, `{"level":"debug","duration":"20ms"}`)
+	res := putLevel(t, h, `{"level":"error"}`)
+	if res.RevertTo != "" || res.RevertAt != nil {
+		t.Errorf("PUT response = %+v, want no pending revert", res)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if got := lv.Level(); got != slog.LevelError {
+		t.Errorf("level = %v, want error to stay", got)
+	}
+}
@@ -1,8 +1,13 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"os"
+	"slices"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-cz/devslog"
@@ -10,8 +15,16 @@ import (
 	"github.com/phsym/console-slog"
 )
 
+// defaultLogger is the last Logger installed with slog.SetDefault, the package level Fatal closes it before exiting
+var defaultLogger atomic.Pointer[Logger]
+
 type Logger struct {
 	*slog.Logger
+	level      *slog.LevelVar
+	async      *AsyncHandler
+	closers    []closer
+	components *componentLevels
+	name       string
 }
 
 type Level slog.Level
@@ -23,6 +36,24 @@ const (
 	LevelDebug Level = Level(slog.LevelDebug)
 )
 
+// Level implements slog.Leveler so a fixed Level can be used as a Sink level
+func (l Level) Level() slog.Level {
+	return slog.Level(l)
+}
+
+func (l Level) String() string {
+	return slog.Level(l).String()
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return slog.Level(l).MarshalText()
+}
+
+// UnmarshalText accepts the slog names e.g. "debug" or "WARN+2" so levels can be read from config
+func (l *Level) UnmarshalText(data []byte) error {
+	return (*slog.Level)(l).UnmarshalText(data)
+}
+
 type Handler string
 
 const (
@@ -31,15 +62,41 @@ const (
 	HandlerConsole Handler = "console"
 	HandlerDevsLog Handler = "devslog"
 	HandlerTint    Handler = "tint"
+
+	// JSON presets shaped for cloud log ingestion, trace_id and span_id attributes are mapped too
+	HandlerGCP     Handler = "gcp"
+	HandlerECS     Handler = "ecs"
+	HandlerDatadog Handler = "datadog"
 )
 
 type (
 	Option        func(*LoggerOptions)
 	LoggerOptions struct {
-		level  Level
-		format Handler
-		output *os.File
-		source bool
+		level      Level
+		levelVar   *slog.LevelVar
+		format     Handler
+		output     io.Writer
+		source     bool
+		redact     *RedactOptions
+		sampling   *SamplingOptions
+		sinks      []Sink
+		async      *AsyncOptions
+		components map[string]Level
+		setDefault bool
+		metrics    *Metrics
+		dedup      *DedupOptions
+		gcpProject string
+		// handlers are written to alongside the format or sink handlers, e.g. by NewTestLogger
+		handlers []slog.Handler
+	}
+
+	// Sink is one output of a logger configured with WithSinks.
+	// Zero values fall back to text, stdout and the logger's level
+	Sink struct {
+		Format Handler
+		Output io.Writer
+		Level  slog.Leveler
+		Source bool
 	}
 )
 
@@ -49,69 +106,208 @@ func WithLevel(level Level) Option {
 	}
 }
 
+// WithLevelVar shares lv as the minimum level so it can be changed at runtime,
+// its current value is used instead of WithLevel
+func WithLevelVar(lv *slog.LevelVar) Option {
+	return func(opts *LoggerOptions) {
+		opts.levelVar = lv
+	}
+}
+
 func WithFormat(format Handler) Option {
 	return func(opts *LoggerOptions) {
 		opts.format = format
 	}
 }
 
+// WithSinks writes every record to each sink that is enabled for its level,
+// replacing the single WithFormat and WithOutput handler. A sink level below the logger's level
+// still receives its records, e.g. a debug file next to an info stdout
+func WithSinks(sinks ...Sink) Option {
+	return func(opts *LoggerOptions) {
+		opts.sinks = append(opts.sinks, sinks...)
+	}
+}
+
 func WithSource(s bool) Option {
 	return func(opts *LoggerOptions) {
 		opts.source = s
 	}
 }
 
-func WithOutput(out *os.File) Option {
+// WithOutput accepts any writer, e.g. a file from NewRotatingFile
+func WithOutput(out io.Writer) Option {
 	return func(opts *LoggerOptions) {
 		opts.output = out
 	}
 }
 
+// WithSetDefault controls whether NewLogger installs the logger with slog.SetDefault, default true.
+// Disable it in libraries and tests that should not change the global logger
+func WithSetDefault(set bool) Option {
+	return func(opts *LoggerOptions) {
+		opts.setDefault = set
+	}
+}
+
+// WithComponentLevels sets the minimum level of loggers created with Named, e.g. db=debug, http=warn.
+// Components without a level use the logger's level
+func WithComponentLevels(levels map[string]Level) Option {
+	return func(opts *LoggerOptions) {
+		opts.components = levels
+	}
+}
+
+// WithGCPProject is the Google Cloud project ID used by HandlerGCP to link trace_id attributes to Cloud Trace,
+// the value is logged as projects/<PROJECT_ID>/traces/<TRACE_ID>
+func WithGCPProject(projectID string) Option {
+	return func(opts *LoggerOptions) {
+		opts.gcpProject = projectID
+	}
+}
+
+// WithRedaction masks secrets and PII in every attribute, see NewRedactHandler
+func WithRedaction(redact RedactOptions) Option {
+	return func(opts *LoggerOptions) {
+		opts.redact = &redact
+	}
+}
+
+// WithSampling drops repeated records on busy paths, see NewSamplingHandler
+func WithSampling(sampling SamplingOptions) Option {
+	return func(opts *LoggerOptions) {
+		opts.sampling = &sampling
+	}
+}
+
+// WithAsync writes records from a background goroutine, see NewAsyncHandler.
+// Call (*Logger).Close on shutdown so queued records are not lost
+func WithAsync(async AsyncOptions) Option {
+	return func(opts *LoggerOptions) {
+		opts.async = &async
+	}
+}
+
+// WithDedup collapses runs of identical records into one line with a repeated count, see NewDedupHandler.
+// Call (*Logger).Close on shutdown so the counts of open windows are logged
+func WithDedup(dedup DedupOptions) Option {
+	return func(opts *LoggerOptions) {
+		opts.dedup = &dedup
+	}
+}
+
+// WithMetrics counts every record in m, including those dropped by sampling, see NewMetrics
+func WithMetrics(m *Metrics) Option {
+	return func(opts *LoggerOptions) {
+		opts.metrics = m
+	}
+}
+
 func NewLogger(options ...Option) *Logger {
 	// default
 	opts := LoggerOptions{
-		level:  LevelInfo,
-		format: HandlerText,
-		output: os.Stdout,
-		source: false,
+		level:      LevelInfo,
+		format:     HandlerText,
+		output:     os.Stdout,
+		source:     false,
+		setDefault: true,
 	}
 
+	return newLogger(opts, options)
+}
+
+// newLogger applies options over the defaults in opts and builds the Logger
+func newLogger(opts LoggerOptions, options []Option) *Logger {
 	for _, opt := range options {
 		opt(&opts)
 	}
 
-	handlerPreset := getHandler(opts)
-	logger := slog.New(handlerPreset)
-	// this allows access via importing slog, however it is better to pass
-	// 	the logger where you can to avoid modifying the global instance
-	slog.SetDefault(logger)
-	return &Logger{
-		slog.New(handlerPreset),
+	if opts.levelVar == nil {
+		opts.levelVar = new(slog.LevelVar)
+		opts.levelVar.Set(slog.Level(opts.level))
+	}
+
+	var sinkLevels []slog.Leveler
+	for _, sink := range opts.sinks {
+		if sink.Level != nil {
+			sinkLevels = append(sinkLevels, sink.Level)
+		}
+	}
+
+	components := newComponentLevels(opts.levelVar, opts.components, sinkLevels)
+	handlerPreset, async, closers := wrapHandler(getHandler(opts, components), opts)
+	logger := slog.New(newComponentHandler(handlerPreset, components.leveler("")))
+	if opts.setDefault {
+		// this allows access via importing slog, however it is better to pass
+		// 	the logger where you can to avoid modifying the global instance
+		slog.SetDefault(logger)
+	}
+	l := &Logger{
+		Logger:     logger,
+		level:      opts.levelVar,
+		async:      async,
+		closers:    closers,
+		components: components,
 	}
+	if opts.setDefault {
+		defaultLogger.Store(l)
+	}
+	return l
+}
+
+// getHandler builds the output handlers, level is the lowest level any component logs at
+func getHandler(opts LoggerOptions, level slog.Leveler) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(opts.sinks)+len(opts.handlers)+1)
+	if len(opts.sinks) == 0 {
+		handlers = append(handlers, newFormatHandler(opts.format, opts.output, opts.source, level, opts.gcpProject))
+	}
+
+	for _, sink := range opts.sinks {
+		format, output, sinkLevel := sink.Format, sink.Output, sink.Level
+		if format == "" {
+			format = HandlerText
+		}
+		if output == nil {
+			output = os.Stdout
+		}
+		if sinkLevel == nil {
+			sinkLevel = level
+		}
+		handlers = append(handlers, newFormatHandler(format, output, sink.Source, sinkLevel, opts.gcpProject))
+	}
+	handlers = append(handlers, opts.handlers...)
+
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return NewMultiHandler(handlers...)
 }
 
-func getHandler(opts LoggerOptions) slog.Handler {
+func newFormatHandler(format Handler, output io.Writer, source bool, level slog.Leveler, gcpProject string) slog.Handler {
 	baseOpts := &slog.HandlerOptions{
-		AddSource: opts.source,
-		Level:     slog.Level(opts.level),
+		AddSource: source,
+		Level:     level,
 	}
 
-	switch opts.format {
+	switch format {
 	case HandlerJSON:
-		return slog.NewJSONHandler(opts.output, baseOpts)
+		return slog.NewJSONHandler(output, baseOpts)
 
 	case HandlerText:
-		return slog.NewTextHandler(opts.output, baseOpts)
+		return slog.NewTextHandler(output, baseOpts)
+
+	case HandlerGCP, HandlerECS, HandlerDatadog:
+		return newPresetHandler(format, output, source, level, gcpProject)
 
 	case HandlerConsole:
-		return console.NewHandler(opts.output, &console.HandlerOptions{
-			AddSource: opts.source,
-			Level:     slog.Level(opts.level),
+		return console.NewHandler(output, &console.HandlerOptions{
+			AddSource: source,
+			Level:     level,
 			Theme:     console.NewBrightTheme(),
 		})
 
 	case HandlerDevsLog:
-		return devslog.NewHandler(opts.output, &devslog.Options{
+		return devslog.NewHandler(output, &devslog.Options{
 			HandlerOptions:    baseOpts,
 			MaxSlicePrintSize: 4,
 			SortKeys:          false,
@@ -124,32 +320,175 @@ func getHandler(opts LoggerOptions) slog.Handler {
 		})
 
 	case HandlerTint:
-		w := os.Stderr
-		return tint.NewHandler(w, &tint.Options{
-			AddSource:  false,
-			Level:      slog.LevelDebug,
+		return tint.NewHandler(output, &tint.Options{
+			AddSource:  source,
+			Level:      level,
 			TimeFormat: time.Kitchen,
 			NoColor:    false,
 		})
 	}
 
-	return slog.NewTextHandler(opts.output, baseOpts)
+	return slog.NewTextHandler(output, baseOpts)
+}
+
+// closer is a handler with background work to stop or records to write out on shutdown
+type closer interface {
+	Close(ctx context.Context) error
+}
+
+// wrapHandler layers the optional handlers on top of the output handler.
+// The async handler is returned as well for Flush, closers are ordered outermost first
+// so records written while closing one handler still pass through the handlers below it
+func wrapHandler(handler slog.Handler, opts LoggerOptions) (slog.Handler, *AsyncHandler, []closer) {
+	var closers []closer
+	if opts.redact != nil {
+		handler = NewRedactHandler(handler, *opts.redact)
+	}
+
+	// inside the context handler so attributes from the context are part of the key
+	var dedup *DedupHandler
+	if opts.dedup != nil {
+		dedup = NewDedupHandler(handler, *opts.dedup)
+		handler = dedup
+		closers = append(closers, dedup)
+	}
+
+	// outside redaction so attributes from the context are redacted too
+	handler = NewContextHandler(handler)
+
+	var async *AsyncHandler
+	if opts.async != nil {
+		async = NewAsyncHandler(handler, *opts.async)
+		handler = async
+		closers = append(closers, async)
+	}
+
+	// outermost so dropped records skip the work done by the other handlers
+	if opts.sampling != nil {
+		sampling := NewSamplingHandler(handler, *opts.sampling)
+		handler = sampling
+		closers = append(closers, sampling)
+	}
+
+	if opts.metrics != nil {
+		handler = NewMetricsHandler(handler, opts.metrics)
+	}
+
+	slices.Reverse(closers)
+	return handler, async, closers
+}
+
+// LevelVar exposes the minimum level, e.g. for NewLevelHandler
+func (l *Logger) LevelVar() *slog.LevelVar {
+	return l.level
+}
+
+func (l *Logger) SetLevel(level Level) {
+	l.level.Set(slog.Level(level))
+}
+
+// Named returns a logger for a component of the application. Its records carry a component attribute
+// and use the level set for name with WithComponentLevels or SetComponentLevel.
+// Calling Named on a named logger joins the names with a dot, e.g. db.pool falls back to the db level
+func (l *Logger) Named(name string) *Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	ch, ok := l.Logger.Handler().(*componentHandler)
+	if !ok {
+		ch = newComponentHandler(l.Logger.Handler(), l.components.leveler(""))
+	}
+
+	named := l.clone(slog.New(ch.named(name, l.components.leveler(name))))
+	named.name = name
+	return named
+}
+
+// SetComponentLevel changes the level of the named component at runtime, it applies to loggers
+// already created with Named
+func (l *Logger) SetComponentLevel(name string, level Level) {
+	l.components.set(name, slog.Level(level))
 }
 
-func (l *Logger) Error(msg string, err error) {
-	l.Logger.Error(msg, slog.Any("error", err))
+// UnsetComponentLevel makes the named component use the logger's level again
+func (l *Logger) UnsetComponentLevel(name string) {
+	l.components.unset(name)
+}
+
+// ComponentLevels returns a copy of the levels set per component
+func (l *Logger) ComponentLevels() map[string]Level {
+	return l.components.snapshot()
+}
+
+// Flush waits for records queued by WithAsync to be written, it is a no-op otherwise
+func (l *Logger) Flush(ctx context.Context) error {
+	if l.async == nil {
+		return nil
+	}
+	return l.async.Flush(ctx)
+}
+
+// Dropped is the number of records WithAsync has dropped because its buffer was full
+func (l *Logger) Dropped() uint64 {
+	if l.async == nil {
+		return 0
+	}
+	return l.async.Dropped()
 }
 
-func (l *Logger) Fatal(msg string, err error) {
-	l.Error(msg, err)
+// Close logs the pending sampling summary, flushes and stops the async writer and logs the counts
+// of open dedup windows. It can be passed to graceful.WithShutDownHandler alongside the server shutdown
+func (l *Logger) Close(ctx context.Context) error {
+	var errs []error
+	for _, c := range l.closers {
+		errs = append(errs, c.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Error logs err as a structured attribute, see Err, followed by args as in slog.Logger.Error
+func (l *Logger) Error(msg string, err error, args ...any) {
+	logCaller(context.Background(), l.Logger, 1, slog.LevelError, msg, errorArgs(err, args)...)
+}
+
+// ErrorContext is Error with ctx passed to the handler, e.g. for attributes added with WithAttrs
+func (l *Logger) ErrorContext(ctx context.Context, msg string, err error, args ...any) {
+	logCaller(ctx, l.Logger, 1, slog.LevelError, msg, errorArgs(err, args)...)
+}
+
+// Fatal logs at LevelError then exits, queued async records are flushed first
+func (l *Logger) Fatal(msg string, err error, args ...any) {
+	logCaller(context.Background(), l.Logger, 1, slog.LevelError, msg, errorArgs(err, args)...)
+	_ = l.Close(context.Background())
 	os.Exit(1)
 }
 
+// With returns a Logger that includes args in each record, as slog.Logger.With
 func (l *Logger) With(args ...any) *Logger {
-	lw := l.Logger.With(args)
-	return &Logger{
-		lw,
+	if len(args) == 0 {
+		return l
 	}
+	return l.clone(l.Logger.With(args...))
+}
+
+// WithGroup returns a Logger that qualifies the attributes of each record with name, as slog.Logger.WithGroup
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	return l.clone(l.Logger.WithGroup(name))
+}
+
+// clone keeps the level, async writer and component of l for a derived slog.Logger
+func (l *Logger) clone(sl *slog.Logger) *Logger {
+	c := *l
+	c.Logger = sl
+	return &c
+}
+
+func errorArgs(err error, args []any) []any {
+	return append([]any{Err(err)}, args...)
 }
 
 /*
@@ -0,0 +1,202 @@
+package logger
+
+import (
+	"cmp"
+	"context"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// OtherMessages is the message key counted once MetricsOptions.MaxMessages distinct messages have been seen
+const OtherMessages = "_other"
+
+// MetricsOptions configures NewMetrics
+type MetricsOptions struct {
+	// ByMessage also counts records per level and message,
+	// keep it off when messages are built with fmt rather than attributes
+	ByMessage bool
+	// MaxMessages bounds the distinct messages counted, default 1000
+	MaxMessages int
+}
+
+type metricKey struct {
+	level slog.Level
+	msg   string
+}
+
+// Metrics counts log records per level and optionally per message, e.g. to alert on the error rate.
+// Records are counted by a handler from NewMetricsHandler or a logger created WithMetrics
+type Metrics struct {
+	opts MetricsOptions
+
+	mu       sync.Mutex
+	levels   map[slog.Level]uint64
+	messages map[metricKey]uint64
+}
+
+// MessageCount is the number of records logged with a level and message
+type MessageCount struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Count   uint64 `json:"count"`
+}
+
+// MetricsSnapshot is a copy of the counters, Levels is keyed by the level name e.g. "ERROR"
+type MetricsSnapshot struct {
+	Levels   map[string]uint64 `json:"levels"`
+	Messages []MessageCount    `json:"messages,omitempty"`
+}
+
+// standardLevels are always reported so rates can be computed before the first record at a level
+var standardLevels = []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+
+func NewMetrics(opts MetricsOptions) *Metrics {
+	if opts.MaxMessages <= 0 {
+		opts.MaxMessages = 1000
+	}
+
+	m := &Metrics{
+		opts:     opts,
+		levels:   make(map[slog.Level]uint64, len(standardLevels)),
+		messages: make(map[metricKey]uint64),
+	}
+	for _, level := range standardLevels {
+		m.levels[level] = 0
+	}
+	return m
+}
+
+func (m *Metrics) count(r slog.Record) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.levels[r.Level]++
+	if !m.opts.ByMessage {
+		return
+	}
+
+	key := metricKey{level: r.Level, msg: r.Message}
+	if _, ok := m.messages[key]; !ok && len(m.messages) >= m.opts.MaxMessages {
+		key.msg = OtherMessages
+	}
+	m.messages[key]++
+}
+
+// Snapshot returns the current counts, messages are ordered by level then message
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snap := MetricsSnapshot{Levels: make(map[string]uint64, len(m.levels))}
+	for level, n := range m.levels {
+		snap.Levels[level.String()] = n
+	}
+
+	keys := make([]metricKey, 0, len(m.messages))
+	for key := range m.messages {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b metricKey) int {
+		return cmp.Or(cmp.Compare(a.level, b.level), strings.Compare(a.msg, b.msg))
+	})
+	for _, key := range keys {
+		snap.Messages = append(snap.Messages, MessageCount{
+			Level:   key.level.String(),
+			Message: key.msg,
+			Count:   m.messages[key],
+		})
+	}
+
+	return snap
+}
+
+// Publish exposes the snapshot with expvar under name, it panics if name is already published
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return m.Snapshot()
+	}))
+}
+
+// ServeHTTP writes the counters in the Prometheus text format, e.g.
+//
+//	log_records_total{level="error"} 12
+//	log_messages_total{level="error",message="error pinging db"} 10
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+// WritePrometheus writes the counters in the Prometheus text format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	snap := m.Snapshot()
+
+	levels := make([]string, 0, len(snap.Levels))
+	for level := range snap.Levels {
+		levels = append(levels, level)
+	}
+	slices.Sort(levels)
+
+	var sb strings.Builder
+	sb.WriteString("# HELP log_records_total Log records by level.\n")
+	sb.WriteString("# TYPE log_records_total counter\n")
+	for _, level := range levels {
+		fmt.Fprintf(&sb, "log_records_total{level=\"%s\"} %d\n", promLabel(strings.ToLower(level)), snap.Levels[level])
+	}
+
+	if m.opts.ByMessage {
+		sb.WriteString("# HELP log_messages_total Log records by level and message.\n")
+		sb.WriteString("# TYPE log_messages_total counter\n")
+		for _, mc := range snap.Messages {
+			fmt.Fprintf(&sb, "log_messages_total{level=\"%s\",message=\"%s\"} %d\n",
+				promLabel(strings.ToLower(mc.Level)), promLabel(mc.Message), mc.Count)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var promReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promLabel(s string) string {
+	return promReplacer.Replace(s)
+}
+
+type metricsHandler struct {
+	next    slog.Handler
+	metrics *Metrics
+}
+
+// NewMetricsHandler counts each record passed to next in m
+func NewMetricsHandler(next slog.Handler, m *Metrics) slog.Handler {
+	return &metricsHandler{next: next, metrics: m}
+}
+
+func (h *metricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *metricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.metrics.count(r)
+	return h.next.Handle(ctx, r)
+}
+
+func (h *metricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &metricsHandler{next: h.next.WithAttrs(attrs), metrics: h.metrics}
+}
+
+func (h *metricsHandler) WithGroup(name string) slog.Handler {
+	return &metricsHandler{next: h.next.WithGroup(name), metrics: h.metrics}
+}
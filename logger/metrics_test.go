@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMetricsCounts(t *testing.T) {
+	m := NewMetrics(MetricsOptions{ByMessage: true, MaxMessages: 2})
+	l := slog.New(NewMetricsHandler(slog.NewTextHandler(io.Discard, nil), m))
+
+	l.Error("error pinging db")
+	l.Error("error pinging db")
+	l.Info("started")
+	l.Info("third message")
+	l.Warn("fourth message")
+	l.With("a", 1).Error("error pinging db")
+
+	snap := m.Snapshot()
+	if snap.Levels["ERROR"] != 3 || snap.Levels["INFO"] != 2 || snap.Levels["WARN"] != 1 || snap.Levels["DEBUG"] != 0 {
+		t.Errorf("Levels = %v, want error 3, info 2, warn 1 and debug 0", snap.Levels)
+	}
+
+	want := []MessageCount{
+		{Level: "INFO", Message: OtherMessages, Count: 1},
+		{Level: "INFO", Message: "started", Count: 1},
+		{Level: "WARN", Message: OtherMessages, Count: 1},
+		{Level: "ERROR", Message: "error pinging db", Count: 3},
+	}
+	if len(snap.Messages) != len(want) {
+		t.Fatalf("Messages = %+v, want %+v", snap.Messages, want)
+	}
+	for i := range want {
+		if snap.Messages[i] != want[i] {
+			t.Errorf("Messages[%d] = %+v, want %+v", i, snap.Messages[i], want[i])
+		}
+	}
+}
+
+func TestMetricsWritePrometheus(t *testing.T) {
+	m := NewMetrics(MetricsOptions{ByMessage: true})
+	l := slog.New(NewMetricsHandler(slog.NewTextHandler(io.Discard, nil), m))
+	l.Error("bad \"quote\"\nand \\ newline")
+
+	var buf bytes.Buffer
+	if err := m.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE log_records_total counter\n",
+		"log_records_total{level=\"error\"} 1\n",
+		"log_records_total{level=\"debug\"} 0\n",
+		"# TYPE log_messages_total counter\n",
+		`log_messages_total{level="error",message="bad \"quote\"\nand \\ newline"} 1` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output has no %q:\n%s", want, out)
+		}
+	}
+	// every sample is on one line
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "log_") {
+			t.Errorf("line %q is not a sample or comment", line)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// context keys renamed by the presets, matching what WithAttrs is typically given
+const (
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+const ecsVersion = "8.11.0"
+
+// newPresetHandler builds a JSON handler shaped for a log ingestion schema,
+// gcpProject is only used by HandlerGCP
+func newPresetHandler(format Handler, output io.Writer, source bool, level slog.Leveler, gcpProject string) slog.Handler {
+	opts := &slog.HandlerOptions{
+		AddSource: source,
+		Level:     level,
+	}
+
+	switch format {
+	case HandlerGCP:
+		opts.ReplaceAttr = replaceGCP(gcpProject)
+	case HandlerECS:
+		opts.ReplaceAttr = replaceECS
+		return slog.NewJSONHandler(output, opts).WithAttrs([]slog.Attr{slog.String("ecs.version", ecsVersion)})
+	case HandlerDatadog:
+		opts.ReplaceAttr = replaceDatadog
+	}
+
+	return slog.NewJSONHandler(output, opts)
+}
+
+// replaceGCP follows https://cloud.google.com/logging/docs/structured-logging.
+// Cloud Logging only links a trace given as projects/<PROJECT_ID>/traces/<TRACE_ID>, so trace_id is
+// left as is when there is no project and the value is not already in that form
+func replaceGCP(project string) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+		return gcpAttr(project, a)
+	}
+}
+
+func gcpAttr(project string, a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.LevelKey:
+		// user attributes keyed level are passed through
+		if lvl, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String("severity", gcpSeverity(lvl))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group("logging.googleapis.com/sourceLocation",
+				slog.String("file", src.File),
+				slog.String("line", strconv.Itoa(src.Line)),
+				slog.String("function", src.Function),
+			)
+		}
+	case traceIDKey:
+		trace := a.Value.String()
+		if !strings.HasPrefix(trace, "projects/") {
+			if project == "" {
+				return a
+			}
+			trace = "projects/" + project + "/traces/" + trace
+		}
+		return slog.String("logging.googleapis.com/trace", trace)
+	case spanIDKey:
+		a.Key = "logging.googleapis.com/spanId"
+	}
+
+	return a
+}
+
+// replaceECS follows https://www.elastic.co/guide/en/ecs/current/ecs-field-reference.html
+func replaceECS(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		if a.Value.Kind() == slog.KindTime {
+			a.Key = "@timestamp"
+		}
+	case slog.LevelKey:
+		if lvl, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String("log.level", levelName(lvl))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group("log.origin",
+				slog.Group("file",
+					slog.String("name", src.File),
+					slog.Int("line", src.Line),
+				),
+				slog.String("function", src.Function),
+			)
+		}
+	case traceIDKey:
+		a.Key = "trace.id"
+	case spanIDKey:
+		a.Key = "span.id"
+	}
+
+	return a
+}
+
+// replaceDatadog follows https://docs.datadoghq.com/logs/log_configuration/attributes_naming_convention.
+// Datadog correlates on the decimal 64 bit IDs, see datadogID
+func replaceDatadog(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		if a.Value.Kind() == slog.KindTime {
+			a.Key = "timestamp"
+		}
+	case slog.LevelKey:
+		if lvl, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String("status", levelName(lvl))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group("logger",
+				slog.String("method_name", src.Function),
+				slog.String("file_name", src.File+":"+strconv.Itoa(src.Line)),
+			)
+		}
+	case traceIDKey:
+		return slog.Attr{Key: "dd.trace_id", Value: datadogID(a.Value)}
+	case spanIDKey:
+		return slog.Attr{Key: "dd.span_id", Value: datadogID(a.Value)}
+	}
+
+	return a
+}
+
+func gcpSeverity(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError+4:
+		return "CRITICAL"
+	case level >= slog.LevelError:
+		return "ERROR"
+	case level >= slog.LevelWarn:
+		return "WARNING"
+	case level >= slog.LevelInfo:
+		return "INFO"
+	}
+	return "DEBUG"
+}
+
+// levelName buckets custom levels into the standard names, e.g. INFO+2 is info
+func levelName(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	}
+	return "debug"
+}
+
+// datadogID converts an OpenTelemetry or W3C trace context ID, 32 or 16 hex characters, to the decimal
+// form Datadog expects. 128 bit trace IDs keep their lower 64 bits as Datadog does. Integers and any
+// other strings are passed through, so log Datadog's own IDs as integers e.g. slog.Uint64
+func datadogID(v slog.Value) slog.Value {
+	switch v.Kind() {
+	case slog.KindInt64, slog.KindUint64:
+		return v
+	}
+
+	s := v.String()
+	if len(s) != 32 && len(s) != 16 {
+		return v
+	}
+	id, err := strconv.ParseUint(s[len(s)-16:], 16, 64)
+	if err != nil {
+		return v
+	}
+	if len(s) == 32 {
+		if _, err := strconv.ParseUint(s[:16], 16, 64); err != nil {
+			return v
+		}
+	}
+	return slog.StringValue(strconv.FormatUint(id, 10))
+}
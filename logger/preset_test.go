@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGCPTrace(t *testing.T) {
+	ctx := WithAttrs(context.Background(), "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	for name, tc := range map[string]struct {
+		project string
+		key     string
+		want    string
+	}{
+		"project":    {project: "my-project", key: "logging.googleapis.com/trace", want: "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736"},
+		"no project": {key: "trace_id", want: "4bf92f3577b34da6a3ce929d0e0e4736"},
+	} {
+		var buf bytes.Buffer
+		l := NewLogger(WithOutput(&buf), WithFormat(HandlerGCP), WithGCPProject(tc.project), WithSetDefault(false))
+		l.InfoContext(ctx, "traced")
+
+		record := decodeLines(t, &buf)[0]
+		if got := record[tc.key]; got != tc.want {
+			t.Errorf("%s: %s = %v, want %s in %v", name, tc.key, got, tc.want, record)
+		}
+	}
+}
+
+func TestDatadogIDs(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithFormat(HandlerDatadog), WithSetDefault(false))
+
+	l.Info("otel", "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7")
+	l.Info("native", "trace_id", uint64(1234), "span_id", "not-an-id")
+
+	records := decodeLines(t, &buf)
+	// the lower 64 bits of the trace ID, a3ce929d0e0e4736
+	if got := records[0]["dd.trace_id"]; got != "11803532876627986230" {
+		t.Errorf("dd.trace_id = %v, want 11803532876627986230", got)
+	}
+	if got := records[0]["dd.span_id"]; got != "67667974448284343" {
+		t.Errorf("dd.span_id = %v, want 67667974448284343", got)
+	}
+	if got := records[1]["dd.trace_id"]; got != 1234.0 {
+		t.Errorf("dd.trace_id = %v, want 1234", got)
+	}
+	if got := records[1]["dd.span_id"]; got != "not-an-id" {
+		t.Errorf("dd.span_id = %v, want it unchanged", got)
+	}
+}
+
+func TestPresetUserLevelAndTimeKeys(t *testing.T) {
+	for name, tc := range map[Handler]struct {
+		level, time string
+	}{
+		HandlerGCP:     {level: "severity", time: "time"},
+		HandlerECS:     {level: "log.level", time: "@timestamp"},
+		HandlerDatadog: {level: "status", time: "timestamp"},
+	} {
+		var buf bytes.Buffer
+		l := NewLogger(WithOutput(&buf), WithFormat(name), WithSetDefault(false))
+		l.Info("level changed", "level", "debug", "time", "yesterday")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("%v: got %d lines, want 1", name, len(lines))
+		}
+		for _, want := range []string{`"` + tc.level + `":"info"`, `"level":"debug"`, `"time":"yesterday"`} {
+			if !strings.Contains(strings.ToLower(lines[0]), strings.ToLower(want)) {
+				t.Errorf("%v: %s has no %s", name, lines[0], want)
+			}
+		}
+		if tc.time != "time" && strings.Count(lines[0], `"`+tc.time+`"`) != 1 {
+			t.Errorf("%v: %s should only rename the record time to %s", name, lines[0], tc.time)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Entry is a record captured by a RecordingHandler.
+// Attrs are resolved and keyed by their full path, grouped attributes are joined with a dot e.g. "error.code"
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]slog.Value
+}
+
+// Attr returns the value stored under the dotted key
+func (e Entry) Attr(key string) (slog.Value, bool) {
+	v, ok := e.Attrs[key]
+	return v, ok
+}
+
+// Match reports whether the entry has every key value pair in args.
+// args are pairs as in slog.Logger.Info, slog.MessageKey matches the message
+func (e Entry) Match(args ...any) bool {
+	for _, want := range argsToAttrs(args) {
+		if want.Key == slog.MessageKey {
+			if e.Message != want.Value.String() {
+				return false
+			}
+			continue
+		}
+
+		got, ok := e.Attrs[want.Key]
+		if !ok || !valueEqual(got, want.Value.Resolve()) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordStore is shared by every handler derived through WithAttrs and WithGroup
+type recordStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// RecordingHandler keeps every enabled record in memory so tests can assert on them
+type RecordingHandler struct {
+	store  *recordStore
+	level  slog.Leveler
+	prefix string
+	attrs  map[string]slog.Value
+}
+
+// NewRecordingHandler records each record at or above level, a nil level records everything
+func NewRecordingHandler(level slog.Leveler) *RecordingHandler {
+	if level == nil {
+		level = slog.Level(-1 << 10)
+	}
+	return &RecordingHandler{
+		store: &recordStore{},
+		level: level,
+	}
+}
+
+func (h *RecordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *RecordingHandler) Handle(_ context.Context, r slog.Record) error {
+	entry := Entry{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   make(map[string]slog.Value, len(h.attrs)+r.NumAttrs()),
+	}
+	for k, v := range h.attrs {
+		entry.Attrs[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flattenAttr(entry.Attrs, h.prefix, a)
+		return true
+	})
+
+	h.store.mu.Lock()
+	h.store.entries = append(h.store.entries, entry)
+	h.store.mu.Unlock()
+	return nil
+}
+
+func (h *RecordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = make(map[string]slog.Value, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		h2.attrs[k] = v
+	}
+	for _, a := range attrs {
+		flattenAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *RecordingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = joinKey(h.prefix, name)
+	return &h2
+}
+
+// Records returns a copy of everything recorded so far, oldest first
+func (h *RecordingHandler) Records() []Entry {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	entries := make([]Entry, len(h.store.entries))
+	copy(entries, h.store.entries)
+	return entries
+}
+
+// Find returns the records at level that match args, see Entry.Match
+func (h *RecordingHandler) Find(level slog.Level, args ...any) []Entry {
+	var found []Entry
+	for _, e := range h.Records() {
+		if e.Level == level && e.Match(args...) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// Has reports whether a record at level matches args e.g.
+// Has(slog.LevelError, "error.code", "generic_not_found")
+func (h *RecordingHandler) Has(level slog.Level, args ...any) bool {
+	return len(h.Find(level, args...)) > 0
+}
+
+// Reset discards the recorded records
+func (h *RecordingHandler) Reset() {
+	h.store.mu.Lock()
+	h.store.entries = nil
+	h.store.mu.Unlock()
+}
+
+// flattenAttr follows the slog rules, empty attributes are dropped and groups with an empty key are inlined
+func flattenAttr(attrs map[string]slog.Value, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		attrs[joinKey(prefix, a.Key)] = a.Value
+		return
+	}
+
+	if a.Key != "" {
+		prefix = joinKey(prefix, a.Key)
+	}
+	for _, ga := range a.Value.Group() {
+		flattenAttr(attrs, prefix, ga)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func argsToAttrs(args []any) []slog.Attr {
+	var r slog.Record
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// valueEqual compares any values with reflect.DeepEqual as slog.Value.Equal panics on uncomparable types
+func valueEqual(a, b slog.Value) bool {
+	if a.Kind() == slog.KindAny && b.Kind() == slog.KindAny {
+		return reflect.DeepEqual(a.Any(), b.Any())
+	}
+	return a.Equal(b)
+}
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redactedMask = "*******"
+
+// cardPattern matches the shape of a card number, matches are only masked when they pass the Luhn check
+var cardPattern = regexp.MustCompile(`\b(?:4\d{3}|5[1-5]\d{2}|3[47]\d{2}|6011)[ -]?\d{4}[ -]?\d{4}[ -]?\d{1,4}\b`)
+
+var (
+	// DefaultRedactKeys are matched case insensitively against any part of an attribute key
+	DefaultRedactKeys = []string{
+		"password", "passwd", "secret", "token", "authorization", "cookie", "api_key", "apikey", "private_key",
+	}
+
+	// DefaultRedactPatterns are replaced wherever they appear in a string value.
+	// The card number pattern only masks numbers with a valid Luhn checksum
+	DefaultRedactPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9\-._~+/]+=*`),
+		regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`),
+		cardPattern,
+	}
+)
+
+// RedactOptions configures NewRedactHandler. Nil Keys or Patterns use the defaults,
+// an empty slice turns that check off
+type RedactOptions struct {
+	Keys     []string
+	Patterns []*regexp.Regexp
+	Mask     string
+}
+
+type redactHandler struct {
+	next     slog.Handler
+	keys     []string
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+// NewRedactHandler masks attributes before they reach next. A matching key masks the whole
+// value, including groups, and matching patterns are masked inside string values.
+// LogValuer values are resolved first so hand written LogValue methods are covered too
+func NewRedactHandler(next slog.Handler, opts RedactOptions) slog.Handler {
+	keys := opts.Keys
+	if keys == nil {
+		keys = DefaultRedactKeys
+	}
+
+	h := &redactHandler{
+		next:     next,
+		keys:     make([]string, len(keys)),
+		patterns: opts.Patterns,
+		mask:     opts.Mask,
+	}
+	for i, key := range keys {
+		h.keys[i] = strings.ToLower(key)
+	}
+	if h.patterns == nil {
+		h.patterns = DefaultRedactPatterns
+	}
+	if h.mask == "" {
+		h.mask = redactedMask
+	}
+
+	return h
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact(a)
+	}
+
+	clone := *h
+	clone.next = h.next.WithAttrs(redacted)
+	return &clone
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+func (h *redactHandler) redact(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if h.sensitiveKey(a.Key) {
+		return slog.String(a.Key, h.mask)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = h.redact(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+
+	case slog.KindString:
+		a.Value = slog.StringValue(h.redactString(a.Value.String()))
+
+	case slog.KindAny:
+		// only values that render as text can be checked, anything else is left to the output handler
+		switch v := a.Value.Any().(type) {
+		case error, fmt.Stringer:
+			s := fmt.Sprint(v)
+			if redacted := h.redactString(s); redacted != s {
+				a.Value = slog.StringValue(redacted)
+			}
+		}
+	}
+
+	return a
+}
+
+func (h *redactHandler) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range h.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *redactHandler) redactString(s string) string {
+	for _, p := range h.patterns {
+		if p != cardPattern {
+			s = p.ReplaceAllString(s, h.mask)
+			continue
+		}
+		s = p.ReplaceAllStringFunc(s, func(match string) string {
+			if !luhnValid(match) {
+				return match
+			}
+			return h.mask
+		})
+	}
+	return s
+}
+
+// luhnValid reports whether the digits of s, ignoring spaces and dashes, pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
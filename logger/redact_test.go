@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"testing"
+)
+
+type account struct {
+	ID    int
+	Token string
+}
+
+func (a account) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", a.ID), slog.String("token", a.Token))
+}
+
+func TestRedactKeys(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(NewRedactHandler(slog.NewJSONHandler(&buf, nil), RedactOptions{}))
+
+	l.With("api_key", "k1").Info("keys",
+		"User_Password", "hunter2",
+		"Authorization", "Basic abc",
+		slog.Group("db", "password", "pw", "host", "h"),
+		slog.Group("secrets", "a", 1),
+		"account", account{ID: 7, Token: "t0k"},
+		"name", "alice",
+	)
+
+	record := decodeLines(t, &buf)[0]
+	for _, key := range []string{"api_key", "User_Password", "Authorization", "secrets"} {
+		if record[key] != redactedMask {
+			t.Errorf("%s = %v, want it masked", key, record[key])
+		}
+	}
+	if db := record["db"].(map[string]any); db["password"] != redactedMask || db["host"] != "h" {
+		t.Errorf("db = %v, want only the password masked", db)
+	}
+	if acc := record["account"].(map[string]any); acc["token"] != redactedMask || acc["id"] != 7.0 {
+		t.Errorf("account = %v, want the LogValue token masked", acc)
+	}
+	if record["name"] != "alice" {
+		t.Errorf("name = %v, want it unchanged", record["name"])
+	}
+}
+
+func TestRedactPatterns(t *testing.T) {
+	for name, tc := range map[string]struct {
+		in, want string
+	}{
+		"bearer":      {in: "header Bearer abc.def-ghi", want: "header " + redactedMask},
+		"email":       {in: "sent to alice@example.com", want: "sent to " + redactedMask},
+		"visa":        {in: "card 4111 1111 1111 1111", want: "card " + redactedMask},
+		"mastercard":  {in: "card 5500-0000-0000-0004", want: "card " + redactedMask},
+		"amex":        {in: "card 378282246310005", want: "card " + redactedMask},
+		"discover":    {in: "card 6011111111111117", want: "card " + redactedMask},
+		"not a card":  {in: "id 4000000000000", want: "id 4000000000000"},
+		"wrong check": {in: "card 4111 1111 1111 1112", want: "card 4111 1111 1111 1112"},
+	} {
+		var buf bytes.Buffer
+		l := slog.New(NewRedactHandler(slog.NewJSONHandler(&buf, nil), RedactOptions{}))
+		l.Info("patterns", "v", tc.in)
+
+		if got := decodeLines(t, &buf)[0]["v"]; got != tc.want {
+			t.Errorf("%s: v = %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestRedactCustomOptions(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(NewRedactHandler(slog.NewJSONHandler(&buf, nil), RedactOptions{
+		Keys:     []string{"ssn"},
+		Patterns: []*regexp.Regexp{},
+		Mask:     "[hidden]",
+	}))
+	l.Info("custom", "ssn", "123", "password", "pw", "email", "alice@example.com")
+
+	record := decodeLines(t, &buf)[0]
+	if record["ssn"] != "[hidden]" || record["password"] != "pw" || record["email"] != "alice@example.com" {
+		t.Errorf("record = %v, want only ssn masked", record)
+	}
+}
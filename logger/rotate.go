@@ -0,0 +1,311 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// backupTimeFormat sorts lexically, it is inserted before the extension e.g. app-2006-01-02T15-04-05.000.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions configures NewRotatingFile, zero values disable that behaviour
+type RotateOptions struct {
+	// MaxSize in bytes before the file is rotated
+	MaxSize int64
+	// Daily rotates on the first write after local midnight
+	Daily bool
+	// MaxBackups is the number of rotated files to keep
+	MaxBackups int
+	// MaxAge removes rotated files older than this
+	MaxAge time.Duration
+	// Compress gzips rotated files
+	Compress bool
+	// ReopenOnSIGHUP reopens the file on SIGHUP for use alongside an external logrotate
+	ReopenOnSIGHUP bool
+}
+
+// RotatingFile is an io.Writer that manages its own rotation and retention
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	day    string
+	closed bool
+
+	mill sync.Mutex
+	wg   sync.WaitGroup
+	stop chan struct{}
+}
+
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path: path,
+		opts: opts,
+		stop: make(chan struct{}),
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	if opts.ReopenOnSIGHUP {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			defer signal.Stop(hup)
+			for {
+				select {
+				case <-f.stop:
+					return
+				case <-hup:
+					// there is nowhere left to report a failure, the next write will return it
+					_ = f.Reopen()
+				}
+			}
+		}()
+	}
+
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate moves the current file to a timestamped backup and starts a new one
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Reopen closes and reopens the path, picking up a new file if it has been moved away
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+	return f.open()
+}
+
+// Close flushes the file and waits for any compression or cleanup still running
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	close(f.stop)
+
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("create log dir: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.day = time.Now().Format(time.DateOnly)
+	return nil
+}
+
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.opts.MaxSize > 0 && f.size > 0 && f.size+n > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.Daily && time.Now().Format(time.DateOnly) != f.day
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+
+	backup := f.backupName(time.Now())
+	if err := os.Rename(f.path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.millBackups(backup)
+	}()
+
+	return nil
+}
+
+// backupName is unique even when rotating more than once a millisecond, as the rename would replace
+// the earlier backup. Compressed backups are checked too as they drop the uncompressed name
+func (f *RotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	for {
+		name := strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeFormat) + ext
+		if !fileExists(name) && !fileExists(name+".gz") {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// millBackups compresses the latest backup and enforces retention, errors are dropped as
+// they cannot be logged without risking a loop
+func (f *RotatingFile) millBackups(backup string) {
+	f.mill.Lock()
+	defer f.mill.Unlock()
+
+	if f.opts.Compress {
+		_ = compressFile(backup)
+	}
+
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := filepath.Base(strings.TrimSuffix(f.path, ext)) + "-"
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, name)
+	}
+	// newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	cutoff := time.Now().Add(-f.opts.MaxAge)
+	for i, name := range backups {
+		path := filepath.Join(filepath.Dir(f.path), name)
+		remove := f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups
+		if !remove && f.opts.MaxAge > 0 {
+			if info, err := os.Stat(path); err == nil && info.ModTime().Before(cutoff) {
+				remove = true
+			}
+		}
+		if remove {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
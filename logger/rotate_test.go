@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// backups lists the rotated files next to path, oldest first
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Name() != filepath.Base(path) {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRotatingFileMaxSizeAndBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := NewRotatingFile(path, RotateOptions{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each 9 byte line after the first pushes the file past MaxSize
+	for _, line := range []string{"line-001\n", "line-002\n", "line-003\n", "line-004\n", "line-005\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Close waits for retention to finish
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readFile(t, path); got != "line-005\n" {
+		t.Errorf("current file = %q, want the last line", got)
+	}
+
+	names := backups(t, path)
+	if len(names) != 2 {
+		t.Fatalf("backups = %v, want the 2 newest", names)
+	}
+	for i, want := range []string{"line-003\n", "line-004\n"} {
+		if !strings.HasPrefix(names[i], "app-") || !strings.HasSuffix(names[i], ".log") {
+			t.Errorf("backup %q is not named app-<time>.log", names[i])
+		}
+		if got := readFile(t, filepath.Join(filepath.Dir(path), names[i])); got != want {
+			t.Errorf("backup %s = %q, want %q", names[i], got, want)
+		}
+	}
+}
+
+func TestRotatingFileCompress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := NewRotatingFile(path, RotateOptions{Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := backups(t, path)
+	if len(names) != 1 || !strings.HasSuffix(names[0], ".log.gz") {
+		t.Fatalf("backups = %v, want one .log.gz", names)
+	}
+
+	gzf, err := os.Open(filepath.Join(filepath.Dir(path), names[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gzf.Close()
+	gz, err := gzip.NewReader(gzf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "first\n" {
+		t.Errorf("backup = %q, want first", b)
+	}
+}
+
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := NewRotatingFile(path, RotateOptions{MaxSize: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 25 {
+				if _, err := f.Write([]byte("0123456789\n")); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// no line may be lost or split across files
+	total := strings.Count(readFile(t, path), "0123456789\n")
+	for _, name := range backups(t, path) {
+		total += strings.Count(readFile(t, filepath.Join(filepath.Dir(path), name)), "0123456789\n")
+	}
+	if total != 100 {
+		t.Errorf("found %d lines across the files, want 100", total)
+	}
+
+	if _, err := f.Write([]byte("late\n")); err == nil {
+		t.Error("Write() after Close error = nil, want os.ErrClosed")
+	}
+}
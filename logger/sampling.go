@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SamplingOptions configures NewSamplingHandler. Records are keyed by level and message
+type SamplingOptions struct {
+	// First records per key are logged each Interval, default 10
+	First int
+	// Thereafter 1 in Thereafter records are logged for the rest of the Interval, 0 drops them all
+	Thereafter int
+	// Interval resets the per key counts, default 1s
+	Interval time.Duration
+	// SummaryInterval is how often a summary of dropped records is logged, default 1m
+	SummaryInterval time.Duration
+	// SampleErrors allows records at LevelError and above to be dropped
+	SampleErrors bool
+}
+
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+type sampleCount struct {
+	start time.Time
+	count int
+}
+
+// samplingState is shared by every handler derived through WithAttrs and WithGroup
+type samplingState struct {
+	root     slog.Handler
+	interval time.Duration
+
+	mu      sync.Mutex
+	counts  map[sampleKey]*sampleCount
+	dropped map[sampleKey]int
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// SamplingHandler drops repeated records, see NewSamplingHandler
+type SamplingHandler struct {
+	next  slog.Handler
+	opts  SamplingOptions
+	state *samplingState
+}
+
+// NewSamplingHandler logs the first N records per level and message each interval and 1 in M after that.
+// A summary of the dropped records is logged every SummaryInterval from a background goroutine,
+// call Close on shutdown to stop it and log the records dropped since the last summary
+func NewSamplingHandler(next slog.Handler, opts SamplingOptions) *SamplingHandler {
+	if opts.First <= 0 {
+		opts.First = 10
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.SummaryInterval <= 0 {
+		opts.SummaryInterval = time.Minute
+	}
+
+	h := &SamplingHandler{
+		next: next,
+		opts: opts,
+		state: &samplingState{
+			root:     next,
+			interval: opts.Interval,
+			counts:   make(map[sampleKey]*sampleCount),
+			dropped:  make(map[sampleKey]int),
+			stop:     make(chan struct{}),
+			done:     make(chan struct{}),
+		},
+	}
+	go h.state.run(opts.SummaryInterval)
+
+	return h
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sample(r) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), opts: h.opts, state: h.state}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), opts: h.opts, state: h.state}
+}
+
+// Close stops the summary goroutine and logs the records dropped since the last summary
+func (h *SamplingHandler) Close(ctx context.Context) error {
+	s := h.state
+	s.closeOnce.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return s.logSummary(ctx, time.Now())
+}
+
+func (h *SamplingHandler) sample(r slog.Record) bool {
+	s := h.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Level >= slog.LevelError && !h.opts.SampleErrors {
+		return true
+	}
+
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	key := sampleKey{level: r.Level, msg: r.Message}
+	c, ok := s.counts[key]
+	if !ok || now.Sub(c.start) >= h.opts.Interval {
+		c = &sampleCount{start: now}
+		s.counts[key] = c
+	}
+	c.count++
+
+	if c.count <= h.opts.First {
+		return true
+	}
+	if h.opts.Thereafter > 0 && (c.count-h.opts.First)%h.opts.Thereafter == 0 {
+		return true
+	}
+
+	s.dropped[key]++
+	return false
+}
+
+func (s *samplingState) run(interval time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			_ = s.logSummary(context.Background(), now)
+		}
+	}
+}
+
+// logSummary writes a record listing what was dropped since the last summary, if anything was
+func (s *samplingState) logSummary(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	summary := s.summary(now)
+	s.mu.Unlock()
+
+	if summary == nil {
+		return nil
+	}
+	return s.root.Handle(ctx, *summary)
+}
+
+// summary builds the dropped record summary, it must be called with mu held
+func (s *samplingState) summary(now time.Time) *slog.Record {
+	// counts are also pruned here so keys that are no longer logged do not build up
+	for key, c := range s.counts {
+		if now.Sub(c.start) >= s.interval {
+			delete(s.counts, key)
+		}
+	}
+
+	if len(s.dropped) == 0 {
+		return nil
+	}
+
+	total := 0
+	messages := make([]any, 0, len(s.dropped))
+	for key, n := range s.dropped {
+		total += n
+		messages = append(messages, slog.Int(key.level.String()+" "+key.msg, n))
+	}
+	s.dropped = make(map[sampleKey]int)
+
+	r := slog.NewRecord(now, slog.LevelWarn, "log records dropped by sampling", 0)
+	r.AddAttrs(
+		slog.Int("dropped", total),
+		slog.Group("messages", messages...),
+	)
+	return &r
+}
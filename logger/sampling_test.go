@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSamplingSummaryAfterBurst(t *testing.T) {
+	rec := NewRecordingHandler(nil)
+	h := NewSamplingHandler(rec, SamplingOptions{First: 2, SummaryInterval: 20 * time.Millisecond})
+	defer h.Close(context.Background())
+
+	l := slog.New(h)
+	for range 5 {
+		l.Info("busy")
+	}
+
+	// nothing else is logged, the summary must still arrive
+	deadline := time.Now().Add(2 * time.Second)
+	for !rec.Has(slog.LevelWarn, slog.MessageKey, "log records dropped by sampling", "dropped", 3) {
+		if time.Now().After(deadline) {
+			t.Fatalf("no summary logged, got %+v", rec.Records())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := len(rec.Find(slog.LevelInfo, slog.MessageKey, "busy")); n != 2 {
+		t.Errorf("logged %d busy records, want 2", n)
+	}
+}
+
+func TestSamplingCloseLogsPendingSummary(t *testing.T) {
+	rec := NewRecordingHandler(nil)
+	h := NewSamplingHandler(rec, SamplingOptions{First: 1, SummaryInterval: time.Hour})
+
+	l := slog.New(h)
+	for range 4 {
+		l.Info("busy")
+	}
+
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Has(slog.LevelWarn, "dropped", 3, "messages.INFO busy", 3) {
+		t.Errorf("no summary logged on close, got %+v", rec.Records())
+	}
+
+	// closing twice is safe and has nothing left to report
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(rec.Find(slog.LevelWarn)); n != 1 {
+		t.Errorf("logged %d summaries, want 1", n)
+	}
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+)
+
+// TestingT is the part of testing.TB used by NewTestLogger
+type TestingT interface {
+	Helper()
+	Log(args ...any)
+	Cleanup(func())
+}
+
+// NewTestLogger records every record for assertions and mirrors it to t.Log so the output
+// interleaves with test failures. options are the same as NewLogger, the level defaults to debug,
+// WithOutput or WithSinks replace the mirror and the slog default is left untouched unless
+// WithSetDefault is given. The logger is closed when the test finishes
+//
+//	log, rec := logger.NewTestLogger(t)
+//	...
+//	if !rec.Has(slog.LevelError, "error.code", "generic_not_found") {
+//		t.Fatal("expected a not found error to be logged")
+//	}
+func NewTestLogger(t TestingT, options ...Option) (*Logger, *RecordingHandler) {
+	t.Helper()
+
+	// records reaching it have already passed the logger's levels
+	rec := NewRecordingHandler(nil)
+	l := newLogger(LoggerOptions{
+		level:    LevelDebug,
+		format:   HandlerText,
+		output:   testWriter{t},
+		handlers: []slog.Handler{rec},
+	}, options)
+
+	t.Cleanup(func() {
+		_ = l.Close(context.Background())
+	})
+
+	return l, rec
+}
+
+// testWriter passes each line to t.Log, slog handlers write one record per call
+type testWriter struct {
+	t TestingT
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.t.Log(string(bytes.TrimRight(p, "\n")))
+	return len(p), nil
+}
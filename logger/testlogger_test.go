@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// codedError carries a code the same way rest.Error does through AttrLogger
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e codedError) Error() string { return e.err.Error() }
+
+func (e codedError) Unwrap() error { return e.err }
+
+func (e codedError) LogAttrs() []slog.Attr {
+	return []slog.Attr{slog.String("code", e.code)}
+}
+
+// fakeT collects what NewTestLogger passes to t.Log and the cleanups it registers
+type fakeT struct {
+	mu       sync.Mutex
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Log(args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, a := range args {
+		f.logs = append(f.logs, a.(string))
+	}
+}
+
+func (f *fakeT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeT) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestTestLoggerRecordsAndMirrors(t *testing.T) {
+	ft := &fakeT{}
+	l, rec := NewTestLogger(ft)
+
+	notFound := codedError{code: "generic_not_found", err: errors.New("no rows")}
+	l.WithGroup("req").With("id", 7).Info("hello", "n", 1)
+	l.Error("lookup failed", notFound)
+	l.Debug("debug is on")
+	ft.finish()
+
+	if !rec.Has(slog.LevelInfo, slog.MessageKey, "hello", "req.id", 7, "req.n", 1) {
+		t.Errorf("grouped record not found in %+v", rec.Records())
+	}
+	if !rec.Has(slog.LevelError, "error.code", "generic_not_found", "error.message", "no rows") {
+		t.Errorf("error record not found in %+v", rec.Records())
+	}
+	if rec.Has(slog.LevelError, "error.code", "other") {
+		t.Error("Has matched a different code")
+	}
+	if len(ft.logs) != 3 || !strings.Contains(ft.logs[0], "msg=hello") {
+		t.Errorf("mirrored %q, want the 3 records", ft.logs)
+	}
+}
+
+func TestTestLoggerOptions(t *testing.T) {
+	var buf bytes.Buffer
+	ft := &fakeT{}
+	l, rec := NewTestLogger(ft, WithOutput(&buf), WithLevel(LevelWarn))
+	l.Info("hidden")
+	l.Warn("shown")
+	ft.finish()
+
+	if len(ft.logs) != 0 {
+		t.Errorf("mirrored %q, want WithOutput to replace t.Log", ft.logs)
+	}
+	if !strings.Contains(buf.String(), "msg=shown") || strings.Contains(buf.String(), "hidden") {
+		t.Errorf("output = %q, want only the warning", buf.String())
+	}
+	if n := len(rec.Records()); n != 1 {
+		t.Errorf("recorded %d records, want 1", n)
+	}
+}
+
+func TestTestLoggerClosesOnCleanup(t *testing.T) {
+	ft := &fakeT{}
+	l, rec := NewTestLogger(ft, WithAsync(AsyncOptions{}), WithDedup(DedupOptions{Window: time.Hour}))
+	for range 3 {
+		l.Info("retry")
+	}
+	ft.finish()
+
+	// the async queue is drained and the open dedup window reported before the test ends
+	if !rec.Has(slog.LevelInfo, slog.MessageKey, "retry", RepeatedKey, 2) {
+		t.Errorf("no repeated record after cleanup, got %+v", rec.Records())
+	}
+}